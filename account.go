@@ -0,0 +1,113 @@
+package emailverifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// defaultAccountURL is the default WhoisXML API account balance URL
+const defaultAccountURL = `https://user.whoisxmlapi.com/user-service/account-balance`
+
+// EvapiProductID is the product identifier of Email Verification API in the account balance
+const EvapiProductID = 7
+
+// AccountService is an interface for WhoisXML API account balance
+type AccountService interface {
+	// Balance returns the account balance for every product
+	Balance(ctx context.Context) ([]ProductBalance, *Response, error)
+
+	// Preflight checks if there are enough Email Verification API credits to verify count addresses
+	// and returns the current balance. The returned error matches ErrInsufficientCredits if there are not
+	Preflight(ctx context.Context, count int) (*ProductBalance, error)
+}
+
+// Product is a WhoisXML API product
+type Product struct {
+	// ID is the product identifier
+	ID int `json:"id"`
+
+	// Name is the product name
+	Name string `json:"name"`
+}
+
+// ProductBalance is the account balance for the product
+type ProductBalance struct {
+	// ProductID is the product identifier
+	ProductID int `json:"product_id"`
+
+	// Product is the product description
+	Product Product `json:"product"`
+
+	// Credits is the number of remaining credits
+	Credits int `json:"credits"`
+}
+
+// accountServiceOp is the type implementing the AccountService interface
+type accountServiceOp struct {
+	client  *Client
+	baseURL *url.URL
+}
+
+var _ AccountService = &accountServiceOp{}
+
+// Balance returns the account balance for every product
+func (service *accountServiceOp) Balance(ctx context.Context) ([]ProductBalance, *Response, error) {
+
+	req, err := service.client.NewRequest(http.MethodGet, service.baseURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	service.client.authorize(req)
+
+	resp, err := service.client.do(ctx, req)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if err = checkResponse(resp); err != nil {
+		return nil, resp, err
+	}
+
+	var balance struct {
+		Data         []ProductBalance `json:"data"`
+		ErrorMessage *ErrorMessage    `json:"ErrorMessage"`
+	}
+
+	if err = json.NewDecoder(bytes.NewReader(resp.Body)).Decode(&balance); err != nil {
+		return nil, resp, fmt.Errorf("cannot parse response: %w", err)
+	}
+
+	if balance.ErrorMessage != nil {
+		return nil, resp, *balance.ErrorMessage
+	}
+
+	return balance.Data, resp, nil
+}
+
+// Preflight checks if there are enough Email Verification API credits to verify count addresses
+func (service *accountServiceOp) Preflight(ctx context.Context, count int) (*ProductBalance, error) {
+
+	balances, _, err := service.Balance(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &ProductBalance{ProductID: EvapiProductID}
+	for i := range balances {
+		if balances[i].ProductID == EvapiProductID {
+			balance = &balances[i]
+			break
+		}
+	}
+
+	if balance.Credits < count {
+		return balance, fmt.Errorf("%w: %d credits required, %d available", ErrInsufficientCredits, count, balance.Credits)
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,72 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// newAccountAPI returns new account balance client for testing
+func newAccountAPI(server *httptest.Server) AccountService {
+
+	accountURL, err := url.Parse(server.URL)
+	if err != nil {
+		panic(err)
+	}
+
+	return NewClient(apiKey, ClientParams{
+		HTTPClient:     server.Client(),
+		AccountBaseURL: accountURL,
+	}).AccountService
+}
+
+// TestAccountBalance tests the Balance and Preflight functions
+func TestAccountBalance(t *testing.T) {
+
+	const resp = `{"data":[{"product_id":1,"product":{"id":1,"name":"WHOIS API"},"credits":500},
+{"product_id":7,"product":{"id":7,"name":"Email Verification API"},"credits":1000}]}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("apiKey") != apiKey {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"ErrorMessage":{"Error":"Access restricted. Check your API key."}}`))
+			return
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+	defer server.Close()
+
+	api := newAccountAPI(server)
+	ctx := context.Background()
+
+	balances, _, err := api.Balance(ctx)
+	if err != nil {
+		t.Fatalf("Account.Balance() error = %v", err)
+	}
+	if len(balances) != 2 || balances[1].Product.Name != "Email Verification API" || balances[1].Credits != 1000 {
+		t.Errorf("Account.Balance() got = %+v, expected 2 products", balances)
+	}
+
+	balance, err := api.Preflight(ctx, 1000)
+	if err != nil || balance.Credits != 1000 {
+		t.Errorf("Account.Preflight() = %+v, %v, expected 1000 credits", balance, err)
+	}
+
+	_, err = api.Preflight(ctx, 1001)
+	if !errors.Is(err, ErrInsufficientCredits) {
+		t.Errorf("Account.Preflight() error = %v, want %v", err, ErrInsufficientCredits)
+	}
+	checkErr(t, err, "insufficient credits: 1001 credits required, 1000 available")
+
+	accountURL, _ := url.Parse(server.URL)
+	_, _, err = NewClient("at_invalid", ClientParams{
+		HTTPClient:     server.Client(),
+		AccountBaseURL: accountURL,
+	}).AccountService.Balance(ctx)
+	if !errors.Is(err, ErrAuthentication) {
+		t.Errorf("Account.Balance() error = %v, want %v", err, ErrAuthentication)
+	}
+}
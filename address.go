@@ -0,0 +1,86 @@
+package emailverifier
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Address is the normalized email address
+type Address struct {
+	// Original is the email address as it was given
+	Original string
+
+	// LocalPart is the local part in Unicode normalization form C
+	LocalPart string
+
+	// Domain is the lower-cased domain name in ASCII (punycode) form as it's reported by the API
+	Domain string
+
+	// UnicodeDomain is the domain name in Unicode form
+	UnicodeDomain string
+}
+
+// ASCII returns the email address with the domain in ASCII form
+func (a Address) ASCII() string {
+	return a.LocalPart + "@" + a.Domain
+}
+
+// Unicode returns the email address with the domain in Unicode form
+func (a Address) Unicode() string {
+	return a.LocalPart + "@" + a.UnicodeDomain
+}
+
+// NormalizeAddress normalizes the internationalized email address. The domain name is converted to punycode
+// according to IDNA2008 and UTS #46 and lower-cased, the local part is converted to Unicode normalization form C
+func NormalizeAddress(emailAddress string) (*Address, error) {
+	trimmed := strings.TrimSpace(emailAddress)
+
+	at := strings.LastIndexByte(trimmed, '@')
+	if at < 0 {
+		return nil, &SyntaxError{EmailAddress: emailAddress, Reason: "missing @"}
+	}
+
+	address := &Address{
+		Original:  emailAddress,
+		LocalPart: norm.NFC.String(trimmed[:at]),
+	}
+
+	domain := trimmed[at+1:]
+	if strings.HasPrefix(domain, "[") {
+		address.Domain, address.UnicodeDomain = domain, domain
+		return address, nil
+	}
+
+	ascii, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return nil, &SyntaxError{EmailAddress: emailAddress, Reason: "invalid internationalized domain: " + err.Error()}
+	}
+	address.Domain = strings.ToLower(ascii)
+
+	unicode, err := idna.Lookup.ToUnicode(address.Domain)
+	if err != nil {
+		unicode = address.Domain
+	}
+	address.UnicodeDomain = unicode
+
+	return address, nil
+}
+
+// normalize returns the normalized email address or nil if it can't be normalized
+func normalize(emailAddress string) *Address {
+	address, err := NormalizeAddress(emailAddress)
+	if err != nil {
+		return nil
+	}
+	return address
+}
+
+// queryAddress returns the email address sent to the API
+func queryAddress(emailAddress string, address *Address) string {
+	if address == nil {
+		return emailAddress
+	}
+	return address.ASCII()
+}
@@ -0,0 +1,116 @@
+package emailverifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestNormalizeAddress tests normalization of the internationalized email addresses
+func TestNormalizeAddress(t *testing.T) {
+
+	tests := []struct {
+		address     string
+		wantASCII   string
+		wantUnicode string
+		wantErr     string
+	}{
+		{
+			address:     "Support@WhoisXMLAPI.com",
+			wantASCII:   "Support@whoisxmlapi.com",
+			wantUnicode: "Support@whoisxmlapi.com",
+		},
+		{
+			address:     " user@пример.рф ",
+			wantASCII:   "user@xn--e1afmkfd.xn--p1ai",
+			wantUnicode: "user@пример.рф",
+		},
+		{
+			address:     "user@ПРИМЕР.РФ",
+			wantASCII:   "user@xn--e1afmkfd.xn--p1ai",
+			wantUnicode: "user@пример.рф",
+		},
+		{
+			address:     "user@XN--E1AFMKFD.xn--p1ai",
+			wantASCII:   "user@xn--e1afmkfd.xn--p1ai",
+			wantUnicode: "user@пример.рф",
+		},
+		{
+			address:     "josé@bücher.de",
+			wantASCII:   "josé@xn--bcher-kva.de",
+			wantUnicode: "josé@bücher.de",
+		},
+		{
+			address:     `"a@b"@example.com`,
+			wantASCII:   `"a@b"@example.com`,
+			wantUnicode: `"a@b"@example.com`,
+		},
+		{
+			address:     "user@[192.168.0.1]",
+			wantASCII:   "user@[192.168.0.1]",
+			wantUnicode: "user@[192.168.0.1]",
+		},
+		{
+			address: "support",
+			wantErr: `invalid email address "support": missing @`,
+		},
+		{
+			address: "user@xn--a.com",
+			wantErr: `invalid email address "user@xn--a.com": invalid internationalized domain: idna: invalid label "\u0080"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			got, err := NormalizeAddress(tt.address)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			if got.Original != tt.address || got.ASCII() != tt.wantASCII || got.Unicode() != tt.wantUnicode {
+				t.Errorf("NormalizeAddress() = %+v, want %s, %s", got, tt.wantASCII, tt.wantUnicode)
+			}
+		})
+	}
+}
+
+// TestEvapiGetNormalize tests that the normalized address is sent to the API
+func TestEvapiGetNormalize(t *testing.T) {
+
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		emailAddress := req.URL.Query().Get("emailAddress")
+		if emailAddress != "user@xn--e1afmkfd.xn--p1ai" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"emailAddress":"` + emailAddress + `","domain":"xn--e1afmkfd.xn--p1ai"}`))
+	}))
+	defer server.Close()
+
+	api := newAPI(server, "")
+
+	evapiResp, resp, err := api.Get(context.Background(), "user@Пример.рф")
+	if err != nil {
+		t.Fatalf("Evapi.Get() error = %v", err)
+	}
+	if resp.Address == nil || resp.Address.Original != "user@Пример.рф" || resp.Address.Domain != evapiResp.Domain {
+		t.Errorf("Evapi.Get() address = %+v, expected domain %s", resp.Address, evapiResp.Domain)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+
+	results := api.GetMany(context.Background(), []string{"user@пример.рф", "user@XN--E1AFMKFD.XN--P1AI", "user@ПРИМЕР.рф"})
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("Evapi.GetMany() result %d error = %v", i, result.Err)
+		}
+	}
+	if c := atomic.LoadInt32(&calls); c != 1 {
+		t.Errorf("server calls = %d, want 1", c)
+	}
+}
@@ -0,0 +1,92 @@
+package emailverifier
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultBatchConcurrency is the default number of concurrent requests made by GetMany
+const defaultBatchConcurrency = 4
+
+// BatchResult is the result of verification of a single email address made by GetMany
+type BatchResult struct {
+	// EmailAddress is the email address as it was passed to GetMany
+	EmailAddress string
+
+	// EvapiResponse is the parsed Email Verification API response, it's nil if Err is not nil.
+	// Duplicate addresses share the same value
+	EvapiResponse *EvapiResponse
+
+	// Response is the raw Email Verification API response if any
+	Response *Response
+
+	// Err is the error occurred while verifying the address
+	Err error
+}
+
+// GetMany returns parsed Email Verification API responses for several email addresses in the input order
+// Identical addresses are requested only once, addresses which differ only in the domain case or form
+// (Unicode or punycode) are considered identical. Requests are made concurrently by a bounded number of workers
+// and fail with the context error once ctx is done
+func (service emailVerifierServiceOp) GetMany(
+	ctx context.Context,
+	emailAddresses []string,
+	opts ...Option,
+) []BatchResult {
+
+	results := make([]BatchResult, len(emailAddresses))
+
+	var unique []string
+	positions := make(map[string][]int)
+	addresses := make(map[string]string)
+
+	for i, emailAddress := range emailAddresses {
+		results[i].EmailAddress = emailAddress
+
+		key := queryAddress(emailAddress, normalize(emailAddress))
+		if _, ok := positions[key]; !ok {
+			unique = append(unique, key)
+			addresses[key] = emailAddress
+		}
+		positions[key] = append(positions[key], i)
+	}
+
+	workers := service.client.batchConcurrency
+	if workers > len(unique) {
+		workers = len(unique)
+	}
+
+	jobs := make(chan string)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for key := range jobs {
+				var result BatchResult
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+				} else {
+					result.EvapiResponse, result.Response, result.Err = service.Get(ctx, addresses[key], opts...)
+				}
+
+				for _, i := range positions[key] {
+					result.EmailAddress = emailAddresses[i]
+					results[i] = result
+				}
+			}
+		}()
+	}
+
+	for _, key := range unique {
+		jobs <- key
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	return results
+}
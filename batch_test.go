@@ -0,0 +1,123 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestEvapiGetMany tests the GetMany function
+func TestEvapiGetMany(t *testing.T) {
+
+	var calls, inFlight, maxInFlight int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		emailAddress := req.URL.Query().Get("emailAddress")
+		if emailAddress == "error@whoisxmlapi.com" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"ErrorMessage":{"Error":"test error message"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"emailAddress":"` + emailAddress + `","formatCheck":"true"}`))
+	}))
+	defer server.Close()
+
+	api := newAPI(server, "")
+	api.batchConcurrency = 2
+
+	emailAddresses := []string{
+		"support@whoisxmlapi.com",
+		"sales@whoisxmlapi.com",
+		"error@whoisxmlapi.com",
+		"support@whoisxmlapi.com",
+		"info@whoisxmlapi.com",
+	}
+
+	results := api.GetMany(context.Background(), emailAddresses)
+
+	if len(results) != len(emailAddresses) {
+		t.Fatalf("Evapi.GetMany() got %d results, want %d", len(results), len(emailAddresses))
+	}
+
+	for i, result := range results {
+		if result.EmailAddress != emailAddresses[i] {
+			t.Errorf("Evapi.GetMany() result %d is for %s, want %s", i, result.EmailAddress, emailAddresses[i])
+		}
+
+		if emailAddresses[i] == "error@whoisxmlapi.com" {
+			if !errors.Is(result.Err, ErrBadRequest) || result.EvapiResponse != nil {
+				t.Errorf("Evapi.GetMany() result %d = %+v, want %v", i, result, ErrBadRequest)
+			}
+			continue
+		}
+
+		if result.Err != nil || result.EvapiResponse == nil || result.EvapiResponse.EmailAddress != emailAddresses[i] {
+			t.Errorf("Evapi.GetMany() result %d = %+v, expected response for %s", i, result, emailAddresses[i])
+		}
+	}
+
+	if results[0].EvapiResponse != results[3].EvapiResponse {
+		t.Errorf("Evapi.GetMany() expected duplicate addresses to share the response")
+	}
+
+	if c := atomic.LoadInt32(&calls); c != 4 {
+		t.Errorf("server calls = %d, want 4", c)
+	}
+
+	if m := atomic.LoadInt32(&maxInFlight); m > 2 {
+		t.Errorf("concurrent requests = %d, want at most 2", m)
+	}
+}
+
+// TestEvapiGetManyCancel tests cancellation of GetMany
+func TestEvapiGetManyCancel(t *testing.T) {
+
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-req.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	api := newAPI(server, "")
+	api.batchConcurrency = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	results := api.GetMany(ctx, []string{"a@whoisxmlapi.com", "b@whoisxmlapi.com", "c@whoisxmlapi.com"})
+
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Evapi.GetMany() took %v, expected to stop on cancellation", d)
+	}
+
+	for i, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("Evapi.GetMany() result %d error = %v, want %v", i, result.Err, context.Canceled)
+		}
+	}
+
+	if c := atomic.LoadInt32(&calls); c != 1 {
+		t.Errorf("server calls = %d, want 1", c)
+	}
+}
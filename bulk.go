@@ -0,0 +1,316 @@
+package emailverifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// defaultBulkURL is the default Bulk Email Verification API URL
+const defaultBulkURL = `https://emailverification.whoisxmlapi.com/api/bev/v1`
+
+const (
+	defaultBulkPollInterval = 5 * time.Second
+	maxBulkPollInterval     = time.Minute
+)
+
+// BulkService is an interface for Bulk Email Verification API
+type BulkService interface {
+	// Create submits the email addresses for verification and returns the created request
+	// It's never retried, so a failed attempt doesn't create duplicate requests
+	Create(ctx context.Context, emailAddresses []string) (*BulkRequest, *Response, error)
+
+	// Status returns the state of the requests with the specified IDs
+	Status(ctx context.Context, ids ...int) ([]BulkRequest, *Response, error)
+
+	// List returns the page of the account requests. Pages are numbered from 1
+	List(ctx context.Context, page, perPage int) ([]BulkRequest, *Response, error)
+
+	// Download returns parsed verification results of the completed request
+	Download(ctx context.Context, id int) ([]BulkResult, *Response, error)
+
+	// DownloadRaw returns raw verification results of the completed request in the specified format: JSON | CSV
+	DownloadRaw(ctx context.Context, id int, format string) (*Response, error)
+
+	// Delete deletes the requests with the specified IDs
+	Delete(ctx context.Context, ids ...int) (*Response, error)
+
+	// WaitForCompletion polls the request status until it's completed or ctx is done.
+	// The polling interval starts from interval and doubles up to a minute. Default interval: 5s.
+	WaitForCompletion(ctx context.Context, id int, interval time.Duration) (*BulkRequest, error)
+}
+
+// BulkRequestState is the processing state of the bulk request
+type BulkRequestState string
+
+const (
+	// BulkRequestPending means no addresses of the request are processed yet
+	BulkRequestPending BulkRequestState = "pending"
+
+	// BulkRequestProcessing means the request is partly processed
+	BulkRequestProcessing BulkRequestState = "processing"
+
+	// BulkRequestCompleted means all addresses of the request are processed and results can be downloaded
+	BulkRequestCompleted BulkRequestState = "completed"
+)
+
+// BulkRequest is a bulk verification request
+type BulkRequest struct {
+	// ID is the request identifier
+	ID int `json:"id"`
+
+	// DateStart is the date the request was created
+	DateStart string `json:"date_start,omitempty"`
+
+	// TotalEmails is the number of addresses in the request
+	TotalEmails int `json:"total_emails"`
+
+	// InvalidEmails is the number of addresses rejected as invalid input
+	InvalidEmails int `json:"invalid_emails"`
+
+	// ProcessedEmails is the number of processed addresses
+	ProcessedEmails int `json:"processed_emails"`
+
+	// FailedEmails is the number of addresses which failed to be verified
+	FailedEmails int `json:"failed_emails"`
+
+	// Ready indicates if the results are ready to be downloaded
+	Ready bool `json:"ready"`
+}
+
+// State returns the processing state of the request
+func (r BulkRequest) State() BulkRequestState {
+	switch {
+	case r.Ready:
+		return BulkRequestCompleted
+	case r.ProcessedEmails+r.FailedEmails == 0:
+		return BulkRequestPending
+	}
+	return BulkRequestProcessing
+}
+
+// BulkResult is a verification result of a single address of the bulk request
+type BulkResult struct {
+	EvapiResponse
+
+	// Result is the overall verification result of the address
+	Result string `json:"result,omitempty"`
+}
+
+// bulkServiceOp is the type implementing the BulkService interface
+type bulkServiceOp struct {
+	client  *Client
+	baseURL *url.URL
+}
+
+var _ BulkService = &bulkServiceOp{}
+
+// bulkEnvelope is used for parsing Bulk Email Verification API responses
+type bulkEnvelope struct {
+	Response     json.RawMessage `json:"response"`
+	ErrorMessage *ErrorMessage   `json:"ErrorMessage"`
+}
+
+// request sends the JSON body with the apiKey to the endpoint and returns the intermediate response
+func (service *bulkServiceOp) request(ctx context.Context, endpoint string, params map[string]interface{}) (*Response, error) {
+
+	u := *service.baseURL
+	if endpoint != "" {
+		u.Path = path.Join(u.Path, endpoint)
+	}
+
+	body := map[string]interface{}{"apiKey": service.client.apiKey}
+	for k, v := range params {
+		body[k] = v
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode request: %w", err)
+	}
+
+	req, err := service.client.NewRequest(http.MethodPost, &u, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := service.client.do(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	if err = checkResponse(resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// call sends the request and parses the response field of the response body into v
+func (service *bulkServiceOp) call(ctx context.Context, endpoint string, params map[string]interface{}, v interface{}) (*Response, error) {
+
+	resp, err := service.request(ctx, endpoint, params)
+	if err != nil {
+		return resp, err
+	}
+
+	var envelope bulkEnvelope
+	if err = json.Unmarshal(resp.Body, &envelope); err != nil {
+		return resp, fmt.Errorf("cannot parse response: %w", err)
+	}
+
+	if envelope.ErrorMessage != nil {
+		return resp, *envelope.ErrorMessage
+	}
+
+	if v == nil {
+		return resp, nil
+	}
+
+	if len(envelope.Response) == 0 {
+		return resp, fmt.Errorf("cannot parse response: empty response")
+	}
+
+	if err = json.Unmarshal(envelope.Response, v); err != nil {
+		return resp, fmt.Errorf("cannot parse response: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Create submits the email addresses for verification and returns the created request
+func (service *bulkServiceOp) Create(ctx context.Context, emailAddresses []string) (*BulkRequest, *Response, error) {
+	if len(emailAddresses) == 0 {
+		return nil, nil, &ArgError{"emailAddresses", "cannot be empty"}
+	}
+
+	// The request isn't idempotent: a retry after a lost response would create a duplicate
+	var request BulkRequest
+	resp, err := service.call(withoutRetry(ctx), "", map[string]interface{}{
+		"emails": emailAddresses,
+		"format": "json",
+	}, &request)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if request.TotalEmails == 0 {
+		request.TotalEmails = len(emailAddresses)
+	}
+
+	return &request, resp, nil
+}
+
+// Status returns the state of the requests with the specified IDs
+func (service *bulkServiceOp) Status(ctx context.Context, ids ...int) ([]BulkRequest, *Response, error) {
+	if len(ids) == 0 {
+		return nil, nil, &ArgError{"ids", "cannot be empty"}
+	}
+
+	var requests []BulkRequest
+	resp, err := service.call(ctx, "requests/status", map[string]interface{}{
+		"ids": ids,
+	}, &requests)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return requests, resp, nil
+}
+
+// List returns the page of the account requests. Pages are numbered from 1
+func (service *bulkServiceOp) List(ctx context.Context, page, perPage int) ([]BulkRequest, *Response, error) {
+	if page < 1 {
+		return nil, nil, &ArgError{"page", "must be positive"}
+	}
+	if perPage < 1 {
+		return nil, nil, &ArgError{"perPage", "must be positive"}
+	}
+
+	var requests []BulkRequest
+	resp, err := service.call(ctx, "requests", map[string]interface{}{
+		"page":    page,
+		"perPage": perPage,
+	}, &requests)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return requests, resp, nil
+}
+
+// Download returns parsed verification results of the completed request
+func (service *bulkServiceOp) Download(ctx context.Context, id int) ([]BulkResult, *Response, error) {
+
+	var results []BulkResult
+	resp, err := service.call(ctx, "requests/completed", map[string]interface{}{
+		"id":     id,
+		"format": "json",
+	}, &results)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return results, resp, nil
+}
+
+// DownloadRaw returns raw verification results of the completed request in the specified format: JSON | CSV
+func (service *bulkServiceOp) DownloadRaw(ctx context.Context, id int, format string) (*Response, error) {
+	if format == "" {
+		return nil, &ArgError{"format", "cannot be empty"}
+	}
+
+	return service.request(ctx, "requests/completed/download", map[string]interface{}{
+		"id":     id,
+		"format": strings.ToLower(format),
+	})
+}
+
+// Delete deletes the requests with the specified IDs
+func (service *bulkServiceOp) Delete(ctx context.Context, ids ...int) (*Response, error) {
+	if len(ids) == 0 {
+		return nil, &ArgError{"ids", "cannot be empty"}
+	}
+
+	return service.call(ctx, "requests/delete", map[string]interface{}{
+		"ids": ids,
+	}, nil)
+}
+
+// WaitForCompletion polls the request status until it's completed or ctx is done
+func (service *bulkServiceOp) WaitForCompletion(ctx context.Context, id int, interval time.Duration) (*BulkRequest, error) {
+	if interval <= 0 {
+		interval = defaultBulkPollInterval
+	}
+
+	for {
+		requests, _, err := service.Status(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range requests {
+			if requests[i].ID == id && requests[i].State() == BulkRequestCompleted {
+				return &requests[i], nil
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if interval *= 2; interval > maxBulkPollInterval {
+			interval = maxBulkPollInterval
+		}
+	}
+}
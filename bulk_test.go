@@ -0,0 +1,291 @@
+package emailverifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// bulkServer is the sample of the Bulk Email Verification API server for testing
+// Every status request processes one more address of the request
+type bulkServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests map[int]*BulkRequest
+	emails   map[int][]string
+	nextID   int
+}
+
+// newBulkServer starts the sample of the Bulk Email Verification API server
+func newBulkServer() *bulkServer {
+	s := &bulkServer{
+		requests: make(map[int]*BulkRequest),
+		emails:   make(map[int][]string),
+		nextID:   1,
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// handle handles the Bulk Email Verification API requests
+func (s *bulkServer) handle(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		APIKey  string   `json:"apiKey"`
+		Emails  []string `json:"emails"`
+		IDs     []int    `json:"ids"`
+		ID      int      `json:"id"`
+		Page    int      `json:"page"`
+		PerPage int      `json:"perPage"`
+		Format  string   `json:"format"`
+	}
+
+	if req.Method != http.MethodPost || json.NewDecoder(req.Body).Decode(&body) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"ErrorMessage":{"Error":"bad request"}}`))
+		return
+	}
+
+	if body.APIKey != apiKey {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"ErrorMessage":{"Error":"Access restricted. Check your API key."}}`))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var response interface{}
+
+	switch req.URL.Path {
+	case "/bev":
+		id := s.nextID
+		s.nextID++
+		s.requests[id] = &BulkRequest{ID: id, DateStart: "1649000000", TotalEmails: len(body.Emails)}
+		s.emails[id] = body.Emails
+		response = map[string]int{"id": id}
+	case "/bev/requests/status":
+		var requests []BulkRequest
+		for _, id := range body.IDs {
+			if r, ok := s.requests[id]; ok {
+				if r.ProcessedEmails < r.TotalEmails {
+					r.ProcessedEmails++
+				}
+				r.Ready = r.ProcessedEmails == r.TotalEmails
+				requests = append(requests, *r)
+			}
+		}
+		response = requests
+	case "/bev/requests":
+		var requests []BulkRequest
+		for id := (body.Page-1)*body.PerPage + 1; id <= body.Page*body.PerPage; id++ {
+			if r, ok := s.requests[id]; ok {
+				requests = append(requests, *r)
+			}
+		}
+		response = requests
+	case "/bev/requests/completed":
+		var results []BulkResult
+		for _, email := range s.emails[body.ID] {
+			formatCheck := StringBool(true)
+			results = append(results, BulkResult{
+				EvapiResponse: EvapiResponse{EmailAddress: email, FormatCheck: &formatCheck},
+				Result:        "valid",
+			})
+		}
+		response = results
+	case "/bev/requests/completed/download":
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte("emailAddress,result\n"))
+		for _, email := range s.emails[body.ID] {
+			_, _ = w.Write([]byte(email + ",valid\n"))
+		}
+		return
+	case "/bev/requests/delete":
+		for _, id := range body.IDs {
+			delete(s.requests, id)
+			delete(s.emails, id)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"response": response})
+}
+
+// newBulkAPI returns new Bulk Email Verification API client for testing
+func newBulkAPI(server *bulkServer, key string) BulkService {
+
+	bulkURL, err := url.Parse(server.URL + "/bev")
+	if err != nil {
+		panic(err)
+	}
+
+	return NewClient(key, ClientParams{
+		HTTPClient:  server.Client(),
+		BulkBaseURL: bulkURL,
+	}).BulkService
+}
+
+// TestBulk tests the Bulk Email Verification API workflow
+func TestBulk(t *testing.T) {
+
+	server := newBulkServer()
+	defer server.Close()
+
+	api := newBulkAPI(server, apiKey)
+	ctx := context.Background()
+
+	emails := []string{"support@whoisxmlapi.com", "sales@whoisxmlapi.com", "info@whoisxmlapi.com"}
+
+	request, _, err := api.Create(ctx, emails)
+	if err != nil {
+		t.Fatalf("Bulk.Create() error = %v", err)
+	}
+	if request.ID != 1 || request.TotalEmails != 3 || request.State() != BulkRequestPending {
+		t.Errorf("Bulk.Create() got = %+v, expected pending request 1", request)
+	}
+
+	requests, _, err := api.Status(ctx, request.ID)
+	if err != nil {
+		t.Fatalf("Bulk.Status() error = %v", err)
+	}
+	if len(requests) != 1 || requests[0].State() != BulkRequestProcessing {
+		t.Errorf("Bulk.Status() got = %+v, expected processing request", requests)
+	}
+
+	completed, err := api.WaitForCompletion(ctx, request.ID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Bulk.WaitForCompletion() error = %v", err)
+	}
+	if completed.State() != BulkRequestCompleted || completed.ProcessedEmails != 3 {
+		t.Errorf("Bulk.WaitForCompletion() got = %+v, expected completed request", completed)
+	}
+
+	results, _, err := api.Download(ctx, request.ID)
+	if err != nil {
+		t.Fatalf("Bulk.Download() error = %v", err)
+	}
+	if len(results) != 3 || results[0].EmailAddress != emails[0] || !bool(*results[0].FormatCheck) {
+		t.Errorf("Bulk.Download() got = %+v, expected results for %v", results, emails)
+	}
+
+	raw, err := api.DownloadRaw(ctx, request.ID, "CSV")
+	if err != nil {
+		t.Fatalf("Bulk.DownloadRaw() error = %v", err)
+	}
+	if want := "emailAddress,result\nsupport@whoisxmlapi.com,valid\nsales@whoisxmlapi.com,valid\n" +
+		"info@whoisxmlapi.com,valid\n"; string(raw.Body) != want {
+		t.Errorf("Bulk.DownloadRaw() got = %q, want %q", string(raw.Body), want)
+	}
+
+	if _, _, err = api.Create(ctx, emails[:1]); err != nil {
+		t.Fatalf("Bulk.Create() error = %v", err)
+	}
+
+	requests, _, err = api.List(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("Bulk.List() error = %v", err)
+	}
+	if len(requests) != 2 {
+		t.Errorf("Bulk.List() got = %+v, expected 2 requests", requests)
+	}
+
+	if _, err = api.Delete(ctx, request.ID); err != nil {
+		t.Fatalf("Bulk.Delete() error = %v", err)
+	}
+
+	requests, _, err = api.List(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("Bulk.List() error = %v", err)
+	}
+	if len(requests) != 1 || requests[0].ID != 2 {
+		t.Errorf("Bulk.List() got = %+v, expected request 2 only", requests)
+	}
+}
+
+// TestBulkErrors tests the Bulk Email Verification API errors
+func TestBulkErrors(t *testing.T) {
+
+	server := newBulkServer()
+	defer server.Close()
+
+	ctx := context.Background()
+
+	_, _, err := newBulkAPI(server, "at_invalid").Create(ctx, []string{"support@whoisxmlapi.com"})
+	if !errors.Is(err, ErrAuthentication) {
+		t.Errorf("Bulk.Create() error = %v, want %v", err, ErrAuthentication)
+	}
+	checkErr(t, err, "API failed with status code: 403 (Access restricted. Check your API key.)")
+
+	api := newBulkAPI(server, apiKey)
+
+	_, _, err = api.Create(ctx, nil)
+	checkErr(t, err, `invalid argument: "emailAddresses" cannot be empty`)
+
+	_, _, err = api.List(ctx, 0, 10)
+	checkErr(t, err, `invalid argument: "page" must be positive`)
+
+	request, _, err := api.Create(ctx, []string{"a@whoisxmlapi.com", "b@whoisxmlapi.com", "c@whoisxmlapi.com"})
+	if err != nil {
+		t.Fatalf("Bulk.Create() error = %v", err)
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+
+	_, err = api.WaitForCompletion(ctxTimeout, request.ID, time.Second)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Bulk.WaitForCompletion() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+// TestBulkCreateNoRetry tests that Create is not retried on server errors
+func TestBulkCreateNoRetry(t *testing.T) {
+
+	var creates, statuses int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/bev" {
+			atomic.AddInt32(&creates, 1)
+		} else {
+			atomic.AddInt32(&statuses, 1)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	bulkURL, err := url.Parse(server.URL + "/bev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := NewClient(apiKey, ClientParams{
+		HTTPClient:  server.Client(),
+		BulkBaseURL: bulkURL,
+		RetryPolicy: &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}).BulkService
+
+	_, resp, err := api.Create(context.Background(), []string{"support@whoisxmlapi.com"})
+	if !errors.Is(err, ErrServerError) {
+		t.Errorf("Bulk.Create() error = %v, want %v", err, ErrServerError)
+	}
+	if c := atomic.LoadInt32(&creates); c != 1 || resp == nil || resp.Attempts != 1 {
+		t.Errorf("Bulk.Create() sent %d requests, want 1", c)
+	}
+
+	_, _, _ = api.Status(context.Background(), 1)
+	if c := atomic.LoadInt32(&statuses); c != 3 {
+		t.Errorf("Bulk.Status() sent %d requests, want 3 retried", c)
+	}
+}
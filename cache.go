@@ -0,0 +1,142 @@
+package emailverifier
+
+import (
+	"container/list"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores Email Verification API responses. Implementations must be safe for concurrent use
+type Cache interface {
+	// Get returns the entry stored with the key
+	Get(key string) (*CacheEntry, bool)
+
+	// Set stores the entry with the key
+	Set(key string, entry *CacheEntry)
+}
+
+// CacheEntry is a cached Email Verification API response
+type CacheEntry struct {
+	// Body is the raw response body
+	Body []byte
+
+	// FetchedAt is the time the response was received from the API
+	FetchedAt time.Time
+
+	// Audit is the data collection and update dates of the response
+	Audit Audit
+}
+
+// cacheKey returns the key of the response for the email address and the query built by opts,
+// and reports whether the cached response must be refreshed
+func cacheKey(emailAddress string, opts []Option) (string, bool) {
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+
+	refresh := query.Get("_hardRefresh") == "1"
+	query.Del("_hardRefresh")
+
+	if i := strings.LastIndexByte(emailAddress, '@'); i >= 0 {
+		emailAddress = emailAddress[:i] + strings.ToLower(emailAddress[i:])
+	}
+
+	return strings.TrimSpace(emailAddress) + "?" + query.Encode(), refresh
+}
+
+// cachedResponse returns the Response for the cache entry
+// The body is copied, so changes made by the caller don't affect the cached entry
+func cachedResponse(entry *CacheEntry) *Response {
+	resp := localResponse(append([]byte(nil), entry.Body...))
+	resp.CacheHit = true
+	return resp
+}
+
+// LRUCache is the in-memory Cache which evicts least recently used entries and entries older than TTL
+type LRUCache struct {
+	mu sync.Mutex
+
+	size int
+	ttl  time.Duration
+
+	entries *list.List
+	index   map[string]*list.Element
+
+	now func() time.Time
+}
+
+// lruItem is the LRUCache list element value
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+var _ Cache = &LRUCache{}
+
+// NewLRUCache creates LRUCache holding up to size entries for ttl. Zero ttl means entries don't expire
+func NewLRUCache(size int, ttl time.Duration) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+
+	return &LRUCache{
+		size:    size,
+		ttl:     ttl,
+		entries: list.New(),
+		index:   make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// Get returns the entry stored with the key if it's not expired
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.index[key]
+	if !ok {
+		return nil, false
+	}
+
+	item := el.Value.(*lruItem)
+	if c.ttl > 0 && c.now().Sub(item.entry.FetchedAt) > c.ttl {
+		c.entries.Remove(el)
+		delete(c.index, key)
+		return nil, false
+	}
+
+	c.entries.MoveToFront(el)
+
+	return item.entry, true
+}
+
+// Set stores the entry with the key evicting the least recently used entry if the cache is full
+func (c *LRUCache) Set(key string, entry *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.index[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		c.entries.MoveToFront(el)
+		return
+	}
+
+	c.index[key] = c.entries.PushFront(&lruItem{key: key, entry: entry})
+
+	for c.entries.Len() > c.size {
+		el := c.entries.Back()
+		c.entries.Remove(el)
+		delete(c.index, el.Value.(*lruItem).key)
+	}
+}
+
+// Len returns the number of entries in the cache including expired ones
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries.Len()
+}
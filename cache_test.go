@@ -0,0 +1,171 @@
+package emailverifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestLRUCache tests eviction of the LRUCache entries
+func TestLRUCache(t *testing.T) {
+
+	now := time.Date(2022, 4, 3, 5, 2, 37, 0, time.UTC)
+
+	c := NewLRUCache(2, time.Hour)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		c.Set(strconv.Itoa(i), &CacheEntry{Body: []byte(strconv.Itoa(i)), FetchedAt: now})
+	}
+
+	if _, ok := c.Get("0"); !ok {
+		t.Errorf("Get() expected entry 0")
+	}
+
+	c.Set("2", &CacheEntry{Body: []byte("2"), FetchedAt: now})
+
+	if _, ok := c.Get("1"); ok {
+		t.Errorf("Get() expected least recently used entry 1 to be evicted")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+
+	c.Set("0", &CacheEntry{Body: []byte("updated"), FetchedAt: now.Add(-2 * time.Hour)})
+
+	if _, ok := c.Get("0"); ok {
+		t.Errorf("Get() expected expired entry 0 to be omitted")
+	}
+	if entry, ok := c.Get("2"); !ok || string(entry.Body) != "2" {
+		t.Errorf("Get() = %v, %v, expected entry 2", entry, ok)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", c.Len())
+	}
+}
+
+// TestCacheKey tests building of the cache keys
+func TestCacheKey(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		address     string
+		opts        []Option
+		want        string
+		wantRefresh bool
+	}{
+		{
+			name:    "domain case",
+			address: "Support@WhoisXMLAPI.com",
+			opts:    []Option{OptionOutputFormat("json")},
+			want:    "Support@whoisxmlapi.com?outputFormat=JSON",
+		},
+		{
+			name:    "options order",
+			address: "support@whoisxmlapi.com",
+			opts:    []Option{OptionValidateSMTP(0), OptionCheckFree(1)},
+			want:    "support@whoisxmlapi.com?checkFree=1&validateSMTP=0",
+		},
+		{
+			name:        "hard refresh",
+			address:     "support@whoisxmlapi.com",
+			opts:        []Option{OptionHardRefresh(1), OptionCheckFree(1)},
+			want:        "support@whoisxmlapi.com?checkFree=1",
+			wantRefresh: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, refresh := cacheKey(tt.address, tt.opts)
+			if got != tt.want || refresh != tt.wantRefresh {
+				t.Errorf("cacheKey() = %v, %v, want %v, %v", got, refresh, tt.want, tt.wantRefresh)
+			}
+		})
+	}
+}
+
+// TestEvapiGetCache tests caching of the Get responses
+func TestEvapiGetCache(t *testing.T) {
+
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if req.URL.Query().Get("emailAddress") == "error@whoisxmlapi.com" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"emailAddress":"` + req.URL.Query().Get("emailAddress") + `","formatCheck":"true"}`))
+	}))
+	defer server.Close()
+
+	api := newAPI(server, "")
+	api.cache = NewLRUCache(10, time.Hour)
+
+	ctx := context.Background()
+
+	steps := []struct {
+		name      string
+		address   string
+		opts      []Option
+		wantHit   bool
+		wantCalls int32
+	}{
+		{name: "miss", address: "support@whoisxmlapi.com", wantHit: false, wantCalls: 1},
+		{name: "hit", address: "support@whoisxmlapi.com", wantHit: true, wantCalls: 1},
+		{name: "other options", address: "support@whoisxmlapi.com", opts: []Option{OptionCheckFree(0)},
+			wantHit: false, wantCalls: 2},
+		{name: "hard refresh", address: "support@whoisxmlapi.com", opts: []Option{OptionHardRefresh(1)},
+			wantHit: false, wantCalls: 3},
+		{name: "hit after refresh", address: "support@whoisxmlapi.com", wantHit: true, wantCalls: 3},
+		{name: "error is not cached", address: "error@whoisxmlapi.com", wantHit: false, wantCalls: 4},
+		{name: "error is requested again", address: "error@whoisxmlapi.com", wantHit: false, wantCalls: 5},
+	}
+	for _, step := range steps {
+		evapiResp, resp, err := api.Get(ctx, step.address, step.opts...)
+		if step.address == "error@whoisxmlapi.com" {
+			checkErr(t, err, "API failed with status code: 400")
+		} else if err != nil || evapiResp.EmailAddress != step.address {
+			t.Errorf("%s: Evapi.Get() = %v, %v, expected response", step.name, evapiResp, err)
+		}
+
+		if resp.CacheHit != step.wantHit {
+			t.Errorf("%s: Response.CacheHit = %v, want %v", step.name, resp.CacheHit, step.wantHit)
+		}
+		if c := atomic.LoadInt32(&calls); c != step.wantCalls {
+			t.Errorf("%s: server calls = %d, want %d", step.name, c, step.wantCalls)
+		}
+	}
+}
+
+// TestEvapiGetCacheCopy tests that changes of the returned body don't affect the cached entry
+func TestEvapiGetCacheCopy(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"emailAddress":"support@whoisxmlapi.com","formatCheck":"true"}`))
+	}))
+	defer server.Close()
+
+	api := newAPI(server, "")
+	api.cache = NewLRUCache(10, time.Hour)
+
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		evapiResp, resp, err := api.Get(ctx, "support@whoisxmlapi.com")
+		if err != nil {
+			t.Fatalf("Evapi.Get() error = %v", err)
+		}
+		if evapiResp.EmailAddress != "support@whoisxmlapi.com" || resp.CacheHit != (i > 0) {
+			t.Errorf("Evapi.Get() = %v, %v, expected response", evapiResp, resp)
+		}
+
+		for j := range resp.Body {
+			resp.Body[j] = 'x'
+		}
+	}
+}
@@ -1,12 +1,13 @@
 package emailverifier
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"strconv"
+	"time"
 )
 
 const (
@@ -27,6 +28,62 @@ type ClientParams struct {
 
 	// EvapiBaseURL is the endpoint for 'Email Verification API' service
 	EvapiBaseURL *url.URL
+
+	// BulkBaseURL is the endpoint for 'Bulk Email Verification API' service
+	BulkBaseURL *url.URL
+
+	// AccountBaseURL is the endpoint for the account balance service
+	AccountBaseURL *url.URL
+
+	// RetryPolicy defines how failed requests are retried
+	// If it's nil then requests are not retried
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter limits the rate of outgoing requests, every retry attempt waits for it as well
+	// The same limiter can be shared between clients which use the same API key
+	// If it's nil then requests are not limited
+	RateLimiter *RateLimiter
+
+	// BatchConcurrency is the maximum number of concurrent requests made by EvapiService.GetMany. Default: 4.
+	BatchConcurrency int
+
+	// Cache stores responses returned by EvapiService.Get, OptionHardRefresh(1) bypasses and refreshes it
+	// If it's nil then responses are not cached
+	Cache Cache
+
+	// ValidateSyntax enables the local email address syntax validation in EvapiService.Get
+	// Addresses with invalid syntax are reported with FormatCheck false without an API request
+	ValidateSyntax bool
+
+	// Instrumentation receives events about EvapiService calls, e.g. to record spans and metrics
+	// If it's nil then calls are not instrumented
+	Instrumentation Instrumentation
+
+	// APIKeyLocation defines where the API key is sent in GET requests. Default: APIKeyInQuery.
+	// Bulk Email Verification API always receives the key in the request body
+	APIKeyLocation APIKeyLocation
+
+	// DomainLists answer the disposable and free checks locally in EvapiService.Get
+	// Listed domains get DisposableCheck and FreeCheck set to true in the responses
+	// If it's nil then only the API checks are used
+	DomainLists *DomainLists
+
+	// DNSChecker checks the mail exchanger of the domain locally in EvapiService.Get. It fills DnsCheck
+	// and MxRecords if the API didn't check DNS and may skip the API request for domains without one
+	// If it's nil then there are no local DNS lookups
+	DNSChecker *DNSChecker
+
+	// Suggester proposes the corrected address in EvapiResponse.Suggestion when the format or DNS check failed
+	// If it's nil then there are no suggestions
+	Suggester *Suggester
+
+	// Middlewares wrap every API request sent by the client. The first middleware is the outermost one:
+	// it sees the request first and the response last. Retries happen inside the innermost middleware
+	Middlewares []Middleware
+
+	// Coalescer makes concurrent EvapiService.Get calls with the same address and options share one request
+	// If it's nil then every call is executed separately
+	Coalescer *Coalescer
 }
 
 // NewBasicClient creates Client with recommended parameters
@@ -47,18 +104,57 @@ func NewClient(apiKey string, params ClientParams) *Client {
 		}
 	}
 
+	bulkBaseURL := params.BulkBaseURL
+	if bulkBaseURL == nil {
+		bulkBaseURL, err = url.Parse(defaultBulkURL)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	accountBaseURL := params.AccountBaseURL
+	if accountBaseURL == nil {
+		accountBaseURL, err = url.Parse(defaultAccountURL)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	httpClient := http.DefaultClient
 	if params.HTTPClient != nil {
 		httpClient = params.HTTPClient
 	}
 
 	client := &Client{
-		client:    httpClient,
-		userAgent: userAgent,
-		apiKey:    apiKey,
+		client:      httpClient,
+		userAgent:   userAgent,
+		apiKey:      apiKey,
+		retryPolicy: params.RetryPolicy,
+		rateLimiter: params.RateLimiter,
+
+		batchConcurrency: params.BatchConcurrency,
+		cache:            params.Cache,
+		validateSyntax:   params.ValidateSyntax,
+		instrumentation:  params.Instrumentation,
+		apiKeyLocation:   params.APIKeyLocation,
+		domainLists:      params.DomainLists,
+		suggester:        params.Suggester,
+		dnsChecker:       params.DNSChecker,
+		coalescer:        params.Coalescer,
+	}
+
+	if client.batchConcurrency <= 0 {
+		client.batchConcurrency = defaultBatchConcurrency
+	}
+
+	client.doer = DoerFunc(client.retry)
+	for i := len(params.Middlewares) - 1; i >= 0; i-- {
+		client.doer = params.Middlewares[i](client.doer)
 	}
 
 	client.EvapiService = &emailVerifierServiceOp{client: client, baseURL: evapiBaseURL}
+	client.BulkService = &bulkServiceOp{client: client, baseURL: bulkBaseURL}
+	client.AccountService = &accountServiceOp{client: client, baseURL: accountBaseURL}
 
 	return client
 }
@@ -70,8 +166,29 @@ type Client struct {
 	userAgent string
 	apiKey    string
 
+	retryPolicy *RetryPolicy
+	rateLimiter *RateLimiter
+
+	batchConcurrency int
+	cache            Cache
+	validateSyntax   bool
+	instrumentation  Instrumentation
+	apiKeyLocation   APIKeyLocation
+	domainLists      *DomainLists
+	suggester        *Suggester
+	dnsChecker       *DNSChecker
+	coalescer        *Coalescer
+
+	doer Doer
+
 	// EmailVerifierService is an interface for Email Verification API
 	EvapiService
+
+	// BulkService is an interface for Bulk Email Verification API
+	BulkService BulkService
+
+	// AccountService is an interface for the account balance
+	AccountService AccountService
 }
 
 // NewRequest creates a basic API request
@@ -95,50 +212,110 @@ func (c *Client) NewRequest(method string, u *url.URL, body io.Reader) (*http.Re
 // Do sends the API request and returns the API response
 func (c *Client) Do(ctx context.Context, req *http.Request, v io.Writer) (response *http.Response, err error) {
 
-	req = req.WithContext(ctx)
+	resp, err := c.do(ctx, req)
+	if resp == nil {
+		return nil, err
+	}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("cannot execute request: %w", err)
+	if _, werr := v.Write(resp.Body); err == nil && werr != nil {
+		err = fmt.Errorf("cannot write response: %w", werr)
 	}
 
-	defer func() {
-		if rerr := resp.Body.Close(); err == nil && rerr != nil {
-			err = fmt.Errorf("cannot close response: %w", rerr)
-		}
-	}()
+	return resp.Response, err
+}
 
-	_, err = io.Copy(v, resp.Body)
-	if err != nil {
-		return resp, fmt.Errorf("cannot read response: %w", err)
+// wait blocks until the rate limiter allows to send the next request
+func (c *Client) wait(ctx context.Context) error {
+	if c.rateLimiter == nil {
+		return nil
+	}
+
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("cannot execute request: %w", err)
 	}
 
-	return resp, err
+	return nil
 }
 
-// ErrorResponse is returned when the response status code is not 2xx
-type ErrorResponse struct {
-	Response *http.Response
-	Message  string
+// do sends the API request through the middlewares and returns the API response
+func (c *Client) do(ctx context.Context, req *http.Request) (*Response, error) {
+	return c.doer.Do(ctx, req)
 }
 
-// Error returns error message as a string
-func (e ErrorResponse) Error() string {
-	if e.Message != "" {
-		return "API failed with status code: " + strconv.Itoa(e.Response.StatusCode) + " (" + e.Message + ")"
+// retry sends the API request, retries it according to the retry policy and returns the last API response
+// Every attempt waits for the rate limiter
+func (c *Client) retry(ctx context.Context, req *http.Request) (*Response, error) {
+
+	req = req.WithContext(ctx)
+
+	var resp *Response
+
+	for attempt := 1; ; attempt++ {
+		if err := c.wait(ctx); err != nil {
+			if attempt == 1 {
+				return nil, err
+			}
+			return withAttempts(resp, err, attempt-1)
+		}
+
+		var err error
+		resp, err = c.send(req)
+		if resp != nil {
+			resp.Attempts = attempt
+		}
+
+		delay, ok := c.retryPolicy.backoff(ctx, attempt, resp, err)
+		if !ok {
+			return withAttempts(resp, err, attempt)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return withAttempts(resp, fmt.Errorf("cannot execute request: %w", ctx.Err()), attempt)
+		case <-timer.C:
+		}
+
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return withAttempts(resp, err, attempt)
+			}
+			body, berr := req.GetBody()
+			if berr != nil {
+				return withAttempts(resp, err, attempt)
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
 	}
-	return "API failed with status code: " + strconv.Itoa(e.Response.StatusCode)
 }
 
-// checkResponse checks if the response status code is not 2xx
-func checkResponse(r *http.Response) error {
-	if c := r.StatusCode; c >= 200 && c <= 299 {
-		return nil
+// send performs a single HTTP request and reads the whole response body
+func (c *Client) send(req *http.Request) (response *Response, err error) {
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot execute request: %w", redactError(err))
+	}
+
+	defer func() {
+		if rerr := resp.Body.Close(); err == nil && rerr != nil {
+			err = fmt.Errorf("cannot close response: %w", rerr)
+		}
+	}()
+
+	var b bytes.Buffer
+	_, err = io.Copy(&b, resp.Body)
+
+	response = &Response{
+		Response: resp,
+		Body:     b.Bytes(),
 	}
 
-	var errorResponse = ErrorResponse{
-		Response: r,
+	if err != nil {
+		return response, fmt.Errorf("cannot read response: %w", err)
 	}
 
-	return errorResponse
+	return response, nil
 }
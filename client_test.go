@@ -2,10 +2,15 @@ package emailverifier
 
 import (
 	"context"
+	"encoding/xml"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -122,7 +127,7 @@ func TestEvapiGet(t *testing.T) {
 				options: "support@whoisxmlapi.com",
 			},
 			want:    false,
-			wantErr: "cannot parse response: invalid character '<' looking for beginning of value",
+			wantErr: "API failed with status code: 500",
 		},
 		{
 			name: "partial response 1",
@@ -152,7 +157,7 @@ func TestEvapiGet(t *testing.T) {
 				options: "support@whoisxmlapi.com",
 			},
 			want:    false,
-			wantErr: "API error: test error message",
+			wantErr: "API failed with status code: 400 (test error message)",
 		},
 		{
 			name: "unparsable response",
@@ -274,7 +279,7 @@ func TestEvapiGetRaw(t *testing.T) {
 				ctx:     ctx,
 				options: "support@whoisxmlapi.com",
 			},
-			wantErr: "API failed with status code: 400",
+			wantErr: "API failed with status code: 400 (test error message)",
 		},
 	}
 	for _, tt := range tests {
@@ -294,3 +299,75 @@ func TestEvapiGetRaw(t *testing.T) {
 		})
 	}
 }
+
+// TestEvapiGetXML tests parsing of the responses in XML format
+func TestEvapiGetXML(t *testing.T) {
+
+	samples := make(map[string][]byte)
+	for _, name := range []string{"response.json", "response.xml", "error.xml"} {
+		b, err := os.ReadFile("testdata/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		samples[name] = b
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		if query.Get("emailAddress") == "error@whoisxmlapi.com" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write(samples["error.xml"])
+			return
+		}
+		if query.Get("outputFormat") == "XML" {
+			_, _ = w.Write(samples["response.xml"])
+			return
+		}
+		_, _ = w.Write(samples["response.json"])
+	}))
+	defer server.Close()
+
+	api := newAPI(server, "")
+	ctx := context.Background()
+
+	want, _, err := api.Get(ctx, "support@whoisxmlapi.com")
+	if err != nil {
+		t.Fatalf("Evapi.Get() error = %v", err)
+	}
+
+	got, resp, err := api.Get(ctx, "support@whoisxmlapi.com", OptionOutputFormat("xml"))
+	if err != nil {
+		t.Fatalf("Evapi.Get() error = %v", err)
+	}
+	if !strings.HasPrefix(string(resp.Body), "<?xml") {
+		t.Errorf("Evapi.Get() body = %s, expected XML", string(resp.Body))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Evapi.Get() XML = %+v, want %+v", got, want)
+	}
+
+	b, err := xml.Marshal(got)
+	if err != nil {
+		t.Fatalf("xml.Marshal() error = %v", err)
+	}
+	decoded, err := parse(b, "XML")
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(&decoded.EvapiResponse, want) {
+		t.Errorf("parse() round trip = %+v, want %+v", decoded.EvapiResponse, want)
+	}
+
+	_, _, err = api.Get(ctx, "error@whoisxmlapi.com", OptionOutputFormat("XML"))
+	checkErr(t, err, "API failed with status code: 400 (test error message)")
+
+	var errMessage ErrorMessage
+	if !errors.As(err, &errMessage) || errMessage.Message != "test error message" {
+		t.Errorf("Evapi.Get() error message = %q, want %q", errMessage.Message, "test error message")
+	}
+
+	evapiResp, err := parse(samples["error.xml"], "XML")
+	if err != nil || evapiResp.ErrorMessage == nil || evapiResp.ErrorMessage.Message != "test error message" {
+		t.Errorf("parse() = %+v, %v, expected error message", evapiResp, err)
+	}
+}
@@ -0,0 +1,399 @@
+// Command emailverifier verifies email addresses with Email Verification API.
+//
+// Usage:
+//
+//	emailverifier [flags] [address ...]
+//
+// Addresses are taken from the arguments, from the file given with -file (TXT with one address per line
+// or CSV with an "email" or "emailAddress" column) or from the standard input.
+// The API key is taken from -apikey or the APIKEY environment variable.
+//
+// Exit codes: 0 if all addresses are valid, 1 if any address is invalid,
+// 2 if any address failed to be verified, 3 on usage errors.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	emailverifier "github.com/whois-api-llc/go-email-verifier"
+)
+
+const (
+	exitValid   = 0
+	exitInvalid = 1
+	exitError   = 2
+	exitUsage   = 3
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv)
+	stop()
+	os.Exit(code)
+}
+
+// config is the command line configuration
+type config struct {
+	apiKey         string
+	baseURL        string
+	file           string
+	format         string
+	raw            bool
+	apiFormat      string
+	concurrency    int
+	retries        int
+	validateSyntax bool
+	opts           []emailverifier.Option
+}
+
+// intOption is the flag setting an integer API option if it's given
+type intOption struct {
+	set    bool
+	value  int
+	option func(int) emailverifier.Option
+}
+
+// String returns the flag value as a string
+func (o *intOption) String() string {
+	if o == nil || !o.set {
+		return ""
+	}
+	return strconv.Itoa(o.value)
+}
+
+// Set parses the flag value
+func (o *intOption) Set(s string) error {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 || v > 1 {
+		return errors.New("must be 0 or 1")
+	}
+	o.set, o.value = true, v
+	return nil
+}
+
+// parseFlags parses the command line arguments and returns the configuration and the addresses
+func parseFlags(args []string, stderr io.Writer, getenv func(string) string) (*config, []string, error) {
+	fs := flag.NewFlagSet("emailverifier", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	cfg := &config{}
+	// The key from the environment is not the flag default, so usage text never shows it
+	fs.StringVar(&cfg.apiKey, "apikey", "", "API key, defaults to the APIKEY environment variable")
+	fs.StringVar(&cfg.baseURL, "base-url", "", "Email Verification API endpoint URL")
+	fs.StringVar(&cfg.file, "file", "", "TXT or CSV file with addresses, - for the standard input")
+	fs.StringVar(&cfg.format, "format", "table", "result format: table | jsonl | csv")
+	fs.BoolVar(&cfg.raw, "raw", false, "print raw API responses")
+	fs.StringVar(&cfg.apiFormat, "output-format", "", "raw API response format: JSON | XML")
+	fs.IntVar(&cfg.concurrency, "concurrency", 4, "maximum number of concurrent requests")
+	fs.IntVar(&cfg.retries, "retries", 2, "number of retries of failed requests")
+	fs.BoolVar(&cfg.validateSyntax, "validate-syntax", false, "reject addresses with invalid syntax without API requests")
+
+	options := []struct {
+		name   string
+		usage  string
+		option func(int) emailverifier.Option
+	}{
+		{"hard-refresh", "get fresh data: 0 | 1", emailverifier.OptionHardRefresh},
+		{"validate-dns", "check the address with DNS: 0 | 1", emailverifier.OptionValidateDNS},
+		{"validate-smtp", "check the address with SMTP: 0 | 1", emailverifier.OptionValidateSMTP},
+		{"check-catch-all", "check for a catch-all address: 0 | 1", emailverifier.OptionCheckCatchAll},
+		{"check-free", "check for a free email provider: 0 | 1", emailverifier.OptionCheckFree},
+		{"check-disposable", "check for a disposable address: 0 | 1", emailverifier.OptionCheckDisposable},
+	}
+	values := make([]*intOption, len(options))
+	for i, o := range options {
+		values[i] = &intOption{option: o.option}
+		fs.Var(values[i], o.name, o.usage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.apiKey == "" {
+		cfg.apiKey = getenv("APIKEY")
+	}
+
+	for _, v := range values {
+		if v.set {
+			cfg.opts = append(cfg.opts, v.option(v.value))
+		}
+	}
+	if cfg.apiFormat != "" {
+		cfg.opts = append(cfg.opts, emailverifier.OptionOutputFormat(cfg.apiFormat))
+	}
+
+	switch cfg.format {
+	case "table", "jsonl", "csv":
+	default:
+		return nil, nil, fmt.Errorf("unknown format %q", cfg.format)
+	}
+
+	if cfg.apiKey == "" {
+		return nil, nil, errors.New("empty API key")
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+// run runs the command and returns the exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	cfg, addresses, err := parseFlags(args, stderr, getenv)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stderr, "emailverifier:", err)
+		}
+		return exitUsage
+	}
+
+	if cfg.file != "" {
+		fileAddresses, err := readFile(cfg.file, stdin)
+		if err != nil {
+			fmt.Fprintln(stderr, "emailverifier:", err)
+			return exitUsage
+		}
+		addresses = append(addresses, fileAddresses...)
+	} else if len(addresses) == 0 {
+		addresses, err = readAddresses(stdin)
+		if err != nil {
+			fmt.Fprintln(stderr, "emailverifier:", err)
+			return exitUsage
+		}
+	}
+
+	if len(addresses) == 0 {
+		fmt.Fprintln(stderr, "emailverifier: no addresses to verify")
+		return exitUsage
+	}
+
+	client, err := newClient(cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, "emailverifier:", err)
+		return exitUsage
+	}
+
+	if cfg.raw {
+		return printRaw(ctx, client, cfg, addresses, stdout, stderr)
+	}
+
+	results := client.EvapiService.GetMany(ctx, addresses, cfg.opts...)
+
+	if err = printResults(stdout, cfg.format, results); err != nil {
+		fmt.Fprintln(stderr, "emailverifier:", err)
+		return exitError
+	}
+
+	code := exitValid
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			code = exitError
+		case !valid(result.EvapiResponse) && code == exitValid:
+			code = exitInvalid
+		}
+	}
+
+	return code
+}
+
+// newClient creates the API client for the configuration
+func newClient(cfg *config) (*emailverifier.Client, error) {
+	params := emailverifier.ClientParams{
+		BatchConcurrency: cfg.concurrency,
+		ValidateSyntax:   cfg.validateSyntax,
+	}
+
+	if cfg.baseURL != "" {
+		u, err := url.Parse(cfg.baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL: %w", err)
+		}
+		params.EvapiBaseURL = u
+	}
+
+	if cfg.retries > 0 {
+		params.RetryPolicy = emailverifier.DefaultRetryPolicy()
+		params.RetryPolicy.MaxAttempts = cfg.retries + 1
+	}
+
+	return emailverifier.NewClient(cfg.apiKey, params), nil
+}
+
+// printRaw prints raw API responses one by one
+func printRaw(ctx context.Context, client *emailverifier.Client, cfg *config, addresses []string,
+	stdout, stderr io.Writer) int {
+
+	code := exitValid
+	for _, address := range addresses {
+		resp, err := client.EvapiService.GetRaw(ctx, address, cfg.opts...)
+		if err != nil {
+			fmt.Fprintf(stderr, "emailverifier: %s: %v\n", address, err)
+			code = exitError
+			continue
+		}
+		fmt.Fprintln(stdout, strings.TrimSpace(string(resp.Body)))
+	}
+
+	return code
+}
+
+// valid reports whether the address passed all performed checks
+func valid(r *emailverifier.EvapiResponse) bool {
+	for _, check := range []*emailverifier.StringBool{r.FormatCheck, r.DnsCheck, r.SmtpCheck} {
+		if check != nil && !bool(*check) {
+			return false
+		}
+	}
+	return r.FormatCheck != nil
+}
+
+// readFile reads addresses from the TXT or CSV file, "-" means the standard input
+func readFile(name string, stdin io.Reader) ([]string, error) {
+	r := stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if strings.EqualFold(filepath.Ext(name), ".csv") {
+		return readCSV(r)
+	}
+
+	return readAddresses(r)
+}
+
+// readAddresses reads addresses one per line skipping empty lines and # comments
+func readAddresses(r io.Reader) ([]string, error) {
+	var addresses []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		addresses = append(addresses, line)
+	}
+
+	return addresses, scanner.Err()
+}
+
+// readCSV reads addresses from the "email" or "emailAddress" column of CSV, or from the first column
+// if there is no header
+func readCSV(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	column := 0
+	for i, name := range records[0] {
+		if n := strings.ToLower(strings.TrimSpace(name)); n == "email" || n == "emailaddress" {
+			column = i
+			records = records[1:]
+			break
+		}
+	}
+
+	var addresses []string
+	for _, record := range records {
+		if column < len(record) {
+			if address := strings.TrimSpace(record[column]); address != "" {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	return addresses, nil
+}
+
+// formatCheck returns the check value as a string
+func formatCheck(check *emailverifier.StringBool) string {
+	if check == nil {
+		return "-"
+	}
+	return strconv.FormatBool(bool(*check))
+}
+
+// row returns the result columns
+func row(result emailverifier.BatchResult) []string {
+	if result.Err != nil {
+		return []string{result.EmailAddress, "error", "-", "-", "-", "-", "-", "-", result.Err.Error()}
+	}
+
+	r := result.EvapiResponse
+	status := "valid"
+	if !valid(r) {
+		status = "invalid"
+	}
+
+	return []string{
+		result.EmailAddress, status,
+		formatCheck(r.FormatCheck), formatCheck(r.DnsCheck), formatCheck(r.SmtpCheck),
+		formatCheck(r.FreeCheck), formatCheck(r.DisposableCheck), formatCheck(r.CatchAllCheck),
+		"",
+	}
+}
+
+var header = []string{"EMAIL", "STATUS", "FORMAT", "DNS", "SMTP", "FREE", "DISPOSABLE", "CATCH-ALL", "ERROR"}
+
+// printResults prints the results in the format
+func printResults(w io.Writer, format string, results []emailverifier.BatchResult) error {
+	switch format {
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, result := range results {
+			line := struct {
+				EmailAddress string                       `json:"emailAddress"`
+				Result       *emailverifier.EvapiResponse `json:"result,omitempty"`
+				Error        string                       `json:"error,omitempty"`
+			}{EmailAddress: result.EmailAddress, Result: result.EvapiResponse}
+			if result.Err != nil {
+				line.Error = result.Err.Error()
+			}
+			if err := enc.Encode(line); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write(header)
+		for _, result := range results {
+			_ = cw.Write(row(result))
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, result := range results {
+		fmt.Fprintln(tw, strings.Join(row(result), "\t"))
+	}
+	return tw.Flush()
+}
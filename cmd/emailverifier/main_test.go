@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeServer is the sample of the Email Verification API server for testing
+// Addresses starting with "invalid" fail the SMTP check, addresses starting with "error" fail with 500
+func fakeServer(queries *[]string) *httptest.Server {
+	var mu sync.Mutex
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		*queries = append(*queries, req.URL.RawQuery)
+		mu.Unlock()
+
+		emailAddress := req.URL.Query().Get("emailAddress")
+		switch {
+		case strings.HasPrefix(emailAddress, "error"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case strings.HasPrefix(emailAddress, "invalid"):
+			_, _ = w.Write([]byte(`{"emailAddress":"` + emailAddress + `","formatCheck":"true","smtpCheck":"false"}`))
+		default:
+			_, _ = w.Write([]byte(`{"emailAddress":"` + emailAddress + `","formatCheck":"true","smtpCheck":"true"}`))
+		}
+	}))
+}
+
+// TestRun tests the command
+func TestRun(t *testing.T) {
+
+	var queries []string
+	server := fakeServer(&queries)
+	defer server.Close()
+
+	dir := t.TempDir()
+	csvFile := filepath.Join(dir, "addresses.csv")
+	if err := os.WriteFile(csvFile, []byte("name,email\nJohn,john@example.com\nJane,jane@example.com\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	getenv := func(key string) string {
+		if key == "APIKEY" {
+			return "at_test"
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		stdin       string
+		wantCode    int
+		wantOut     []string
+		wantQueries []string
+	}{
+		{
+			name:     "valid address",
+			args:     []string{"-base-url", server.URL, "support@whoisxmlapi.com"},
+			wantCode: exitValid,
+			wantOut:  []string{"EMAIL", "support@whoisxmlapi.com  valid"},
+		},
+		{
+			name:     "invalid address",
+			args:     []string{"-base-url", server.URL, "-format", "csv", "support@whoisxmlapi.com", "invalid@whoisxmlapi.com"},
+			wantCode: exitInvalid,
+			wantOut:  []string{"invalid@whoisxmlapi.com,invalid,true,-,false,-,-,-,"},
+		},
+		{
+			name:     "error",
+			args:     []string{"-base-url", server.URL, "-retries", "0", "-format", "jsonl", "error@whoisxmlapi.com", "invalid@whoisxmlapi.com"},
+			wantCode: exitError,
+			wantOut: []string{
+				`{"emailAddress":"error@whoisxmlapi.com","error":"API failed with status code: 500"}`,
+				`"smtpCheck":"false"`,
+			},
+		},
+		{
+			name:     "stdin",
+			args:     []string{"-base-url", server.URL, "-format", "jsonl"},
+			stdin:    "# comment\na@example.com\n\nb@example.com\n",
+			wantCode: exitValid,
+			wantOut:  []string{`"emailAddress":"a@example.com"`, `"emailAddress":"b@example.com"`},
+		},
+		{
+			name:     "CSV file",
+			args:     []string{"-base-url", server.URL, "-file", csvFile, "-format", "csv"},
+			wantCode: exitValid,
+			wantOut:  []string{"john@example.com,valid", "jane@example.com,valid"},
+		},
+		{
+			name:        "options",
+			args:        []string{"-base-url", server.URL, "-check-free", "0", "-hard-refresh", "1", "a@example.com"},
+			wantCode:    exitValid,
+			wantQueries: []string{"_hardRefresh=1", "checkFree=0"},
+		},
+		{
+			name:        "raw",
+			args:        []string{"-base-url", server.URL, "-raw", "-output-format", "xml", "a@example.com"},
+			wantCode:    exitValid,
+			wantOut:     []string{`{"emailAddress":"a@example.com","formatCheck":"true","smtpCheck":"true"}`},
+			wantQueries: []string{"outputFormat=XML"},
+		},
+		{
+			name:     "syntax validation",
+			args:     []string{"-base-url", server.URL, "-validate-syntax", "-format", "csv", "foo@@bar"},
+			wantCode: exitInvalid,
+			wantOut:  []string{"foo@@bar,invalid,false"},
+		},
+		{
+			name:     "invalid option",
+			args:     []string{"-check-free", "2", "a@example.com"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "unknown format",
+			args:     []string{"-format", "xml", "a@example.com"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "no addresses",
+			args:     []string{"-base-url", server.URL},
+			wantCode: exitUsage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries = nil
+
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tt.args, strings.NewReader(tt.stdin), &stdout, &stderr, getenv)
+
+			if code != tt.wantCode {
+				t.Errorf("run() = %d, want %d, stderr: %s", code, tt.wantCode, stderr.String())
+			}
+
+			for _, want := range tt.wantOut {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("run() output:\n%s\nexpected to contain %q", stdout.String(), want)
+				}
+			}
+
+			for _, want := range tt.wantQueries {
+				if len(queries) == 0 || !strings.Contains(queries[0], want) {
+					t.Errorf("run() queries = %v, expected to contain %q", queries, want)
+				}
+			}
+		})
+	}
+}
+
+// TestUsageHidesAPIKey tests that the usage text never contains the API key from the environment
+func TestUsageHidesAPIKey(t *testing.T) {
+
+	getenv := func(key string) string {
+		if key == "APIKEY" {
+			return "at_supersecret"
+		}
+		return ""
+	}
+
+	for _, args := range [][]string{{"-bogus"}, {"-h"}, {"-format", "xml", "support@whoisxmlapi.com"}} {
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), args, strings.NewReader(""), &stdout, &stderr, getenv)
+
+		if code != exitUsage {
+			t.Errorf("run(%v) = %d, want %d", args, code, exitUsage)
+		}
+		if strings.Contains(stderr.String()+stdout.String(), "supersecret") {
+			t.Errorf("run(%v) output contains the API key:\n%s", args, stderr.String())
+		}
+	}
+
+	cfg, _, err := parseFlags([]string{"support@whoisxmlapi.com"}, &bytes.Buffer{}, getenv)
+	if err != nil || cfg.apiKey != "at_supersecret" {
+		t.Errorf("parseFlags() API key = %v, %v, expected the key from the environment", cfg, err)
+	}
+}
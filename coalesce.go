@@ -0,0 +1,221 @@
+package emailverifier
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Coalescer makes concurrent EvapiService.Get calls with the same normalized address and options
+// share one call: the first one is executed and its result is returned to all of them.
+// Every caller waits for the result with its own context. The shared call is canceled
+// only when all callers have given up. It must not be shared between clients
+type Coalescer struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+
+	calls     uint64
+	executed  uint64
+	coalesced uint64
+	abandoned uint64
+}
+
+// CoalescerState is a snapshot of the Coalescer counters
+type CoalescerState struct {
+	// InFlight is the number of calls currently executed
+	InFlight int
+
+	// Calls is the total number of calls which passed the coalescer
+	Calls uint64
+
+	// Executed is the total number of calls which were executed
+	Executed uint64
+
+	// Coalesced is the total number of calls which shared the result of another call, i.e. the saved calls
+	Coalesced uint64
+
+	// Abandoned is the total number of executed calls canceled because all their callers had given up
+	Abandoned uint64
+}
+
+// flight is the executed call shared by the callers
+type flight struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	// waiters is the number of callers waiting for the result
+	waiters int
+
+	evapiResp *EvapiResponse
+	resp      *Response
+	err       error
+}
+
+// NewCoalescer creates Coalescer
+func NewCoalescer() *Coalescer {
+	return &Coalescer{flights: make(map[string]*flight)}
+}
+
+// State returns the current counters of the coalescer
+func (c *Coalescer) State() CoalescerState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CoalescerState{
+		InFlight:  len(c.flights),
+		Calls:     c.calls,
+		Executed:  c.executed,
+		Coalesced: c.coalesced,
+		Abandoned: c.abandoned,
+	}
+}
+
+// do executes the call with the key or joins the same call in flight and waits for its result
+func (c *Coalescer) do(
+	ctx context.Context,
+	key string,
+	call func(ctx context.Context) (*EvapiResponse, *Response, error),
+) (*EvapiResponse, *Response, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	c.calls++
+
+	f, ok := c.flights[key]
+	if ok {
+		c.coalesced++
+	} else {
+		c.executed++
+
+		// The call must outlive the caller which started it while other callers wait
+		flightCtx, cancel := context.WithCancel(detachedContext{ctx})
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		c.flights[key] = f
+
+		go c.execute(flightCtx, key, f, call)
+	}
+	f.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return shareResult(f)
+	case <-ctx.Done():
+	}
+
+	c.mu.Lock()
+	f.waiters--
+	if f.waiters == 0 && c.flights[key] == f {
+		select {
+		case <-f.done:
+		default:
+			delete(c.flights, key)
+			c.abandoned++
+			f.cancel()
+		}
+	}
+	c.mu.Unlock()
+
+	return nil, nil, ctx.Err()
+}
+
+// execute runs the call and publishes its result
+func (c *Coalescer) execute(
+	ctx context.Context,
+	key string,
+	f *flight,
+	call func(ctx context.Context) (*EvapiResponse, *Response, error),
+) {
+	defer f.cancel()
+
+	evapiResp, resp, err := call(ctx)
+
+	c.mu.Lock()
+	if c.flights[key] == f {
+		delete(c.flights, key)
+	}
+	f.evapiResp, f.resp, f.err = evapiResp, resp, err
+	close(f.done)
+	c.mu.Unlock()
+}
+
+// shareResult returns the copies of the flight result, so callers can modify them without affecting each other
+// The check values, MX records, body and headers are copied, the request of http.Response is shared
+func shareResult(f *flight) (*EvapiResponse, *Response, error) {
+	return copyEvapiResponse(f.evapiResp), copyResponse(f.resp), f.err
+}
+
+// copyEvapiResponse returns the deep copy of the response
+func copyEvapiResponse(r *EvapiResponse) *EvapiResponse {
+	if r == nil {
+		return nil
+	}
+
+	copied := *r
+	for _, check := range []**StringBool{
+		&copied.FormatCheck, &copied.SmtpCheck, &copied.DnsCheck,
+		&copied.FreeCheck, &copied.DisposableCheck, &copied.CatchAllCheck,
+	} {
+		if *check != nil {
+			value := **check
+			*check = &value
+		}
+	}
+	if r.MxRecords != nil {
+		copied.MxRecords = append([]string(nil), r.MxRecords...)
+	}
+
+	return &copied
+}
+
+// copyResponse returns the copy of the response with its own body, headers and address
+func copyResponse(r *Response) *Response {
+	if r == nil {
+		return nil
+	}
+
+	copied := *r
+	if r.Body != nil {
+		copied.Body = append([]byte(nil), r.Body...)
+	}
+	if r.Address != nil {
+		address := *r.Address
+		copied.Address = &address
+	}
+	if r.Response != nil {
+		httpResp := *r.Response
+		httpResp.Header = r.Header.Clone()
+		httpResp.Trailer = r.Trailer.Clone()
+		copied.Response = &httpResp
+	}
+
+	return &copied
+}
+
+// detachedContext keeps the values of the parent context but not its deadline and cancellation
+type detachedContext struct {
+	parent context.Context
+}
+
+// Deadline returns no deadline
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done returns nil, the context is never canceled
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err returns nil, the context is never canceled
+func (detachedContext) Err() error {
+	return nil
+}
+
+// Value returns the value of the parent context
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,180 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newCoalescingAPI returns the client with Coalescer and the server which answers once release is closed
+func newCoalescingAPI(calls *int32, release chan struct{}, canceled chan struct{}) (*Client, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(calls, 1)
+		select {
+		case <-release:
+		case <-req.Context().Done():
+			close(canceled)
+			return
+		}
+		_, _ = w.Write([]byte(`{"emailAddress":"` + req.URL.Query().Get("emailAddress") + `","formatCheck":"true"}`))
+	}))
+
+	api := newAPI(server, "")
+	api.coalescer = NewCoalescer()
+
+	return api, server
+}
+
+// TestCoalescer tests that concurrent identical calls share one request
+func TestCoalescer(t *testing.T) {
+
+	var calls int32
+	release := make(chan struct{})
+
+	api, server := newCoalescingAPI(&calls, release, make(chan struct{}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	const callers = 5
+
+	var wg sync.WaitGroup
+	responses := make([]*EvapiResponse, callers+1)
+	errs := make([]error, callers+1)
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			address := "support@whoisxmlapi.com"
+			if i%2 == 1 {
+				address = "support@WhoisXmlApi.com"
+			}
+			responses[i], _, errs[i] = api.Get(ctx, address)
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		responses[callers], _, errs[callers] = api.Get(ctx, "support@whoisxmlapi.com", OptionCheckFree(0))
+	}()
+
+	for api.coalescer.State().Calls < callers+1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	for i := range responses {
+		if errs[i] != nil || responses[i].EmailAddress != "support@whoisxmlapi.com" {
+			t.Errorf("Get() = %v, %v, expected response", responses[i], errs[i])
+		}
+	}
+	if responses[0] == responses[1] || responses[0].FormatCheck == responses[1].FormatCheck {
+		t.Error("Get() expected separate copies of the shared response")
+	}
+
+	if c := atomic.LoadInt32(&calls); c != 2 {
+		t.Errorf("server calls = %d, want 2", c)
+	}
+
+	state := api.coalescer.State()
+	if state.Calls != callers+1 || state.Executed != 2 || state.Coalesced != callers-1 || state.InFlight != 0 {
+		t.Errorf("State() = %+v", state)
+	}
+}
+
+// TestCoalescerCancel tests the per-caller context cancellation
+func TestCoalescerCancel(t *testing.T) {
+
+	var calls int32
+	release := make(chan struct{})
+	canceled := make(chan struct{})
+
+	api, server := newCoalescingAPI(&calls, release, canceled)
+	defer server.Close()
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	second, cancelSecond := context.WithCancel(context.Background())
+	defer cancelSecond()
+
+	results := make(chan error, 2)
+	for _, ctx := range []context.Context{first, second} {
+		go func(ctx context.Context) {
+			_, _, err := api.Get(ctx, "support@whoisxmlapi.com")
+			results <- err
+		}(ctx)
+	}
+
+	for atomic.LoadInt32(&calls) < 1 || api.coalescer.State().Calls < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The caller which started the request gives up, but the request continues for the other one
+	cancelFirst()
+	if err := <-results; !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() error = %v, want %v", err, context.Canceled)
+	}
+
+	select {
+	case <-canceled:
+		t.Fatal("request canceled while a caller is waiting")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// The request is canceled when all callers have given up
+	cancelSecond()
+	if err := <-results; !errors.Is(err, context.Canceled) {
+		t.Errorf("Get() error = %v, want %v", err, context.Canceled)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("request is not canceled")
+	}
+
+	if state := api.coalescer.State(); state.Abandoned != 1 || state.InFlight != 0 {
+		t.Errorf("State() = %+v", state)
+	}
+
+	close(release)
+	if _, _, err := api.Get(context.Background(), "support@whoisxmlapi.com"); err != nil {
+		t.Errorf("Get() error = %v after the abandoned call", err)
+	}
+}
+
+// TestShareResult tests that callers get independent copies of the shared result
+func TestShareResult(t *testing.T) {
+	yes := StringBool(true)
+
+	resp := NewResponse(http.StatusOK, []byte("body"))
+	resp.Header.Set("X-Test", "1")
+	resp.Address = &Address{Domain: "whoisxmlapi.com"}
+
+	f := &flight{
+		evapiResp: &EvapiResponse{SmtpCheck: &yes, MxRecords: []string{"mx.whoisxmlapi.com."}},
+		resp:      resp,
+	}
+
+	evapiResp, copied, _ := shareResult(f)
+
+	*evapiResp.SmtpCheck = false
+	evapiResp.MxRecords[0] = "changed"
+	copied.Body[0] = 'B'
+	copied.Header.Set("X-Test", "2")
+	copied.Address.Domain = "changed"
+
+	if !bool(*f.evapiResp.SmtpCheck) || f.evapiResp.MxRecords[0] != "mx.whoisxmlapi.com." {
+		t.Errorf("shared response changed: %+v", f.evapiResp)
+	}
+	if string(resp.Body) != "body" || resp.Header.Get("X-Test") != "1" || resp.Address.Domain != "whoisxmlapi.com" {
+		t.Errorf("shared Response changed: %s %v %+v", resp.Body, resp.Header, resp.Address)
+	}
+}
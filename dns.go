@@ -0,0 +1,201 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const defaultNegativeTTL = 5 * time.Minute
+
+// maxNegativeEntries is the number of cached negative results which triggers removal of the expired ones
+const maxNegativeEntries = 10000
+
+// ErrNoMailExchanger is returned by DNSChecker.LookupMX for domains which can't receive emails
+var ErrNoMailExchanger = errors.New("no mail exchanger")
+
+// Resolver looks up DNS records. It's implemented by *net.Resolver
+type Resolver interface {
+	// LookupMX returns the MX records of the domain sorted by preference
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+
+	// LookupHost returns the addresses of the host
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+var _ Resolver = &net.Resolver{}
+
+// DNSChecker checks locally that the email address domain has a mail exchanger
+// A domain without MX records falls back to its A/AAAA records as the implicit mail exchanger,
+// a null MX record (RFC 7505) means that the domain doesn't accept emails
+type DNSChecker struct {
+	// Resolver is used for DNS lookups. If it's nil then net.DefaultResolver is used
+	Resolver Resolver
+
+	// NegativeTTL is the time domains without a mail exchanger are cached. Default: 5m.
+	// Negative values disable the cache
+	NegativeTTL time.Duration
+
+	// ShortCircuit makes EvapiService.Get look up the domain before the API request and return a synthesized
+	// response with DnsCheck false without the request for domains without a mail exchanger.
+	// Otherwise the domain is looked up only if the API response has no DnsCheck
+	ShortCircuit bool
+
+	mu       sync.Mutex
+	negative map[string]time.Time
+
+	now func() time.Time
+}
+
+// NewDNSChecker creates DNSChecker with the resolver. If it's nil then net.DefaultResolver is used
+func NewDNSChecker(resolver Resolver) *DNSChecker {
+	return &DNSChecker{Resolver: resolver}
+}
+
+// LookupMX returns the mail exchangers of the domain in the order of preference as the API reports them,
+// i.e. fully qualified with the trailing dot. It returns ErrNoMailExchanger if there are none
+func (c *DNSChecker) LookupMX(ctx context.Context, domain string) ([]string, error) {
+	records, err := c.LookupMXRecords(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]string, len(records))
+	for i, mx := range records {
+		hosts[i] = mx.Host + "."
+	}
+
+	return hosts, nil
+}
+
+// LookupMXRecords returns the MX records of the domain sorted by preference
+// The implicit mail exchanger of a domain without MX records has no preference
+// It returns ErrNoMailExchanger if there are none
+func (c *DNSChecker) LookupMXRecords(ctx context.Context, domain string) ([]MXRecord, error) {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return nil, &ArgError{"domain", "cannot be empty"}
+	}
+
+	if c.cachedNegative(domain) {
+		return nil, ErrNoMailExchanger
+	}
+
+	resolver := c.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	records, err := resolver.LookupMX(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return nil, fmt.Errorf("cannot look up MX records: %w", err)
+	}
+
+	var parsed []MXRecord
+	for _, mx := range records {
+		if host := normalizeHost(mx.Host); host != "" {
+			preference := mx.Pref
+			parsed = append(parsed, MXRecord{Host: host, Preference: &preference, Provider: ClassifyMX(host)})
+		}
+	}
+	if len(parsed) > 0 {
+		sortMXRecords(parsed)
+		return parsed, nil
+	}
+
+	if len(records) == 0 {
+		addrs, err := resolver.LookupHost(ctx, domain)
+		if err != nil && !isNotFound(err) {
+			return nil, fmt.Errorf("cannot look up host: %w", err)
+		}
+		if len(addrs) > 0 {
+			return []MXRecord{{Host: domain, Provider: ClassifyMX(domain)}}, nil
+		}
+	}
+
+	c.cacheNegative(domain)
+
+	return nil, ErrNoMailExchanger
+}
+
+// Check sets DnsCheck and MxRecords of the response as the API does
+func (c *DNSChecker) Check(ctx context.Context, r *EvapiResponse) error {
+	hosts, err := c.LookupMX(ctx, responseDomain(r))
+	if err != nil && !errors.Is(err, ErrNoMailExchanger) {
+		return err
+	}
+
+	dnsCheck := StringBool(err == nil)
+	r.DnsCheck = &dnsCheck
+	r.MxRecords = hosts
+
+	return nil
+}
+
+// cachedNegative reports whether the domain is cached as one without a mail exchanger
+func (c *DNSChecker) cachedNegative(domain string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires, ok := c.negative[domain]
+	if !ok {
+		return false
+	}
+
+	if !c.timeNow().Before(expires) {
+		delete(c.negative, domain)
+		return false
+	}
+
+	return true
+}
+
+// cacheNegative caches the domain without a mail exchanger
+func (c *DNSChecker) cacheNegative(domain string) {
+	ttl := c.NegativeTTL
+	if ttl == 0 {
+		ttl = defaultNegativeTTL
+	}
+	if ttl < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.timeNow()
+
+	if c.negative == nil {
+		c.negative = make(map[string]time.Time)
+	}
+
+	if len(c.negative) >= maxNegativeEntries {
+		for d, expires := range c.negative {
+			if !now.Before(expires) {
+				delete(c.negative, d)
+			}
+		}
+	}
+	if len(c.negative) >= maxNegativeEntries {
+		return
+	}
+
+	c.negative[domain] = now.Add(ttl)
+}
+
+// timeNow returns the current time
+func (c *DNSChecker) timeNow() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// isNotFound reports whether the DNS lookup failed because there are no records
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
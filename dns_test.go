@@ -0,0 +1,191 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeResolver is the in-memory Resolver
+type fakeResolver struct {
+	mx      map[string][]*net.MX
+	hosts   map[string][]string
+	fail    map[string]bool
+	lookups int32
+}
+
+var _ Resolver = &fakeResolver{}
+
+// LookupMX returns the MX records of the domain
+func (r *fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	atomic.AddInt32(&r.lookups, 1)
+	if r.fail[name] {
+		return nil, &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+	}
+	if records, ok := r.mx[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+// LookupHost returns the addresses of the host
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+// newFakeResolver returns the resolver with a few domains
+func newFakeResolver() *fakeResolver {
+	return &fakeResolver{
+		mx: map[string][]*net.MX{
+			"whoisxmlapi.com": {{Host: "ASPMX.L.GOOGLE.COM.", Pref: 1}, {Host: "alt1.aspmx.l.google.com", Pref: 5}},
+			"nullmx.example":  {{Host: ".", Pref: 0}},
+		},
+		hosts: map[string][]string{
+			"a-only.example": {"192.0.2.1"},
+			"nullmx.example": {"192.0.2.2"},
+		},
+		fail: map[string]bool{"broken.example": true},
+	}
+}
+
+// TestDNSChecker tests the local MX lookups
+func TestDNSChecker(t *testing.T) {
+	resolver := newFakeResolver()
+	checker := NewDNSChecker(resolver)
+
+	now := time.Now()
+	checker.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	tests := []struct {
+		domain string
+		want   []string
+		err    error
+	}{
+		{"WhoisXMLAPI.com.", []string{"aspmx.l.google.com.", "alt1.aspmx.l.google.com."}, nil},
+		{"a-only.example", []string{"a-only.example."}, nil},
+		{"nullmx.example", nil, ErrNoMailExchanger},
+		{"missing.example", nil, ErrNoMailExchanger},
+	}
+	for _, tt := range tests {
+		got, err := checker.LookupMX(ctx, tt.domain)
+		if !reflect.DeepEqual(got, tt.want) || !errors.Is(err, tt.err) {
+			t.Errorf("LookupMX(%q) = %v, %v, want %v, %v", tt.domain, got, err, tt.want, tt.err)
+		}
+	}
+
+	if _, err := checker.LookupMX(ctx, "broken.example"); err == nil || errors.Is(err, ErrNoMailExchanger) {
+		t.Errorf("LookupMX() error = %v, expected lookup error", err)
+	}
+
+	lookups := atomic.LoadInt32(&resolver.lookups)
+	if _, err := checker.LookupMX(ctx, "missing.example"); !errors.Is(err, ErrNoMailExchanger) {
+		t.Errorf("LookupMX() error = %v, want %v", err, ErrNoMailExchanger)
+	}
+	if got := atomic.LoadInt32(&resolver.lookups); got != lookups {
+		t.Errorf("negative result is not cached: %d lookups, want %d", got, lookups)
+	}
+
+	now = now.Add(defaultNegativeTTL)
+	_, _ = checker.LookupMX(ctx, "missing.example")
+	if got := atomic.LoadInt32(&resolver.lookups); got != lookups+1 {
+		t.Errorf("negative result is not expired: %d lookups, want %d", got, lookups+1)
+	}
+
+	r := &EvapiResponse{EmailAddress: "support@whoisxmlapi.com"}
+	if err := checker.Check(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if r.DnsCheck == nil || !bool(*r.DnsCheck) || len(r.MxRecords) != 2 {
+		t.Errorf("Check() = %+v", r)
+	}
+}
+
+// TestDNSCheckerGet tests the local DNS check in Get
+func TestDNSCheckerGet(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		emailAddress := req.URL.Query().Get("emailAddress")
+		if strings.HasPrefix(emailAddress, "checked@") {
+			_, _ = w.Write([]byte(`{"emailAddress":"` + emailAddress + `","formatCheck":"true","dnsCheck":"true"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"emailAddress":"` + emailAddress + `","formatCheck":"true"}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolver := newFakeResolver()
+	checker := NewDNSChecker(resolver)
+
+	api := NewClient(apiKey, ClientParams{
+		HTTPClient:   server.Client(),
+		EvapiBaseURL: apiURL,
+		DNSChecker:   checker,
+		Suggester:    DefaultSuggester(),
+	})
+
+	ctx := context.Background()
+
+	// The API checked DNS, so there is no local lookup
+	evapiResp, _, err := api.Get(ctx, "checked@missing.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evapiResp.DnsCheck == nil || !bool(*evapiResp.DnsCheck) || atomic.LoadInt32(&resolver.lookups) != 0 {
+		t.Errorf("Get() = %+v, lookups = %d, expected the API DNS check", evapiResp, resolver.lookups)
+	}
+
+	evapiResp, _, err = api.Get(ctx, "support@whoisxmlapi.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evapiResp.DnsCheck == nil || !bool(*evapiResp.DnsCheck) || evapiResp.MxRecords[0] != "aspmx.l.google.com." {
+		t.Errorf("Get() = %+v, expected DNS check filled", evapiResp)
+	}
+
+	evapiResp, resp, err := api.Get(ctx, "john@missing.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Synthesized || evapiResp.DnsCheck == nil || bool(*evapiResp.DnsCheck) {
+		t.Errorf("Get() = %+v, expected DNS check failed", evapiResp)
+	}
+
+	checker.ShortCircuit = true
+
+	evapiResp, resp, err = api.Get(ctx, "john@gmial.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Synthesized || evapiResp.DnsCheck == nil || bool(*evapiResp.DnsCheck) ||
+		evapiResp.FormatCheck == nil || !bool(*evapiResp.FormatCheck) || evapiResp.Suggestion != "john@gmail.com" {
+		t.Errorf("Get() = %+v, %v, expected synthesized response", evapiResp, resp)
+	}
+
+	if _, _, err = api.Get(ctx, "john@broken.example"); err != nil {
+		t.Errorf("Get() error = %v, expected the API to be used on lookup errors", err)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 4 {
+		t.Errorf("requests = %d, want 4", got)
+	}
+}
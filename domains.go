@@ -0,0 +1,214 @@
+package emailverifier
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/idna"
+)
+
+//go:embed data/disposable_domains.txt
+var disposableDomains []byte
+
+//go:embed data/free_domains.txt
+var freeDomains []byte
+
+// DomainList is the set of domains matching the domains and their subdomains. It's safe for concurrent use
+type DomainList struct {
+	mu      sync.RWMutex
+	domains map[string]struct{}
+}
+
+// NewDomainList creates the domain list with the domains
+func NewDomainList(domains ...string) *DomainList {
+	l := &DomainList{domains: make(map[string]struct{})}
+	l.Add(domains...)
+	return l
+}
+
+// ReadDomainList creates the domain list from the reader with one domain per line. Empty lines and # comments
+// are skipped
+func ReadDomainList(r io.Reader) (*DomainList, error) {
+	l := NewDomainList()
+	if err := l.AddFrom(r); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// LoadDomainList creates the domain list from the file with one domain per line
+func LoadDomainList(name string) (*DomainList, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read domain list: %w", err)
+	}
+	defer f.Close()
+
+	return ReadDomainList(f)
+}
+
+// DisposableDomains returns the embedded list of disposable email address domains
+// Every call returns a new list, so it can be updated without affecting the others
+func DisposableDomains() *DomainList {
+	l, _ := ReadDomainList(bytes.NewReader(disposableDomains))
+	return l
+}
+
+// FreeDomains returns the embedded list of free email provider domains
+// Every call returns a new list, so it can be updated without affecting the others
+func FreeDomains() *DomainList {
+	l, _ := ReadDomainList(bytes.NewReader(freeDomains))
+	return l
+}
+
+// Add adds the domains to the list
+func (l *DomainList) Add(domains ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, d := range domains {
+		if d = normalizeDomain(d); d != "" {
+			l.domains[d] = struct{}{}
+		}
+	}
+}
+
+// Remove removes the domains from the list
+func (l *DomainList) Remove(domains ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, d := range domains {
+		delete(l.domains, normalizeDomain(d))
+	}
+}
+
+// AddFrom adds the domains from the reader with one domain per line. Empty lines and # comments are skipped
+func (l *DomainList) AddFrom(r io.Reader) error {
+	var domains []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("cannot read domain list: %w", err)
+	}
+
+	l.Add(domains...)
+	return nil
+}
+
+// Len returns the number of domains in the list
+func (l *DomainList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.domains)
+}
+
+// Match returns the listed domain which is the domain or its parent
+func (l *DomainList) Match(domain string) (string, bool) {
+	domain = normalizeDomain(domain)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for domain != "" {
+		if _, ok := l.domains[domain]; ok {
+			return domain, true
+		}
+
+		i := strings.IndexByte(domain, '.')
+		if i < 0 {
+			break
+		}
+		domain = domain[i+1:]
+	}
+
+	return "", false
+}
+
+// Contains reports whether the domain or its parent is in the list
+func (l *DomainList) Contains(domain string) bool {
+	_, ok := l.Match(domain)
+	return ok
+}
+
+// normalizeDomain returns the lower-cased domain in ASCII form without the trailing dot
+func normalizeDomain(domain string) string {
+	domain = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+	if ascii, err := idna.Lookup.ToASCII(domain); err == nil {
+		domain = ascii
+	}
+	return strings.ToLower(domain)
+}
+
+// DomainLists answers the disposable and free checks locally
+type DomainLists struct {
+	// Disposable is the list of disposable email address domains. Nil disables the local disposable check
+	Disposable *DomainList
+
+	// Free is the list of free email provider domains. Nil disables the local free check
+	Free *DomainList
+
+	// SkipDisposable makes Get return a synthesized response without an API request for disposable domains
+	SkipDisposable bool
+}
+
+// DefaultDomainLists returns the embedded disposable and free domain lists
+func DefaultDomainLists() *DomainLists {
+	return &DomainLists{
+		Disposable: DisposableDomains(),
+		Free:       FreeDomains(),
+	}
+}
+
+// IsDisposable reports whether the domain is in the disposable domain list
+func (l *DomainLists) IsDisposable(domain string) bool {
+	return l.Disposable != nil && l.Disposable.Contains(domain)
+}
+
+// IsFree reports whether the domain is in the free domain list
+func (l *DomainLists) IsFree(domain string) bool {
+	return l.Free != nil && l.Free.Contains(domain)
+}
+
+// Prefill sets DisposableCheck and FreeCheck to true if the response domain is in the lists
+// Checks of the domains which are not listed are left as they are
+func (l *DomainLists) Prefill(r *EvapiResponse) {
+	domain := responseDomain(r)
+
+	if l.IsDisposable(domain) {
+		disposable := StringBool(true)
+		r.DisposableCheck = &disposable
+	}
+
+	if l.IsFree(domain) {
+		free := StringBool(true)
+		r.FreeCheck = &free
+	}
+}
+
+// skip returns the response for the address if it must not be sent to the API
+func (l *DomainLists) skip(emailAddress string, address *Address) (*EvapiResponse, bool) {
+	if !l.SkipDisposable || address == nil || !l.IsDisposable(address.Domain) {
+		return nil, false
+	}
+
+	evapiResp := checkedLocally(emailAddress, address)
+	l.Prefill(evapiResp)
+
+	return evapiResp, true
+}
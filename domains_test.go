@@ -0,0 +1,138 @@
+package emailverifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestDomainList tests the domain lists
+func TestDomainList(t *testing.T) {
+	disposable := DisposableDomains()
+	free := FreeDomains()
+
+	if disposable.Len() == 0 || free.Len() == 0 {
+		t.Fatalf("embedded lists are empty: %d disposable, %d free", disposable.Len(), free.Len())
+	}
+
+	tests := []struct {
+		list   *DomainList
+		domain string
+		want   string
+	}{
+		{disposable, "mailinator.com", "mailinator.com"},
+		{disposable, "Inbox.Mailinator.COM.", "mailinator.com"},
+		{disposable, "notmailinator.com", ""},
+		{disposable, "com", ""},
+		{free, "gmail.com", "gmail.com"},
+		{free, "whoisxmlapi.com", ""},
+		{free, "", ""},
+	}
+	for _, tt := range tests {
+		got, ok := tt.list.Match(tt.domain)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("Match(%q) = %q, %v, want %q", tt.domain, got, ok, tt.want)
+		}
+	}
+
+	custom, err := ReadDomainList(strings.NewReader("# custom list\n\nexample.org\n  Bücher.example  \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if custom.Len() != 2 || !custom.Contains("mail.example.org") || !custom.Contains("xn--bcher-kva.example") {
+		t.Errorf("ReadDomainList() = %v", custom.domains)
+	}
+
+	custom.Add("example.net")
+	custom.Remove("EXAMPLE.ORG")
+	if !custom.Contains("example.net") || custom.Contains("example.org") {
+		t.Errorf("Add(), Remove() = %v", custom.domains)
+	}
+
+	name := filepath.Join(t.TempDir(), "domains.txt")
+	if err = os.WriteFile(name, []byte("example.com\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := LoadDomainList(name)
+	if err != nil || !fromFile.Contains("example.com") {
+		t.Errorf("LoadDomainList() = %v, %v", fromFile, err)
+	}
+
+	if _, err = LoadDomainList(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("LoadDomainList() expected error")
+	}
+
+	if DisposableDomains().Add("example.com"); DisposableDomains().Contains("example.com") {
+		t.Errorf("DisposableDomains() returned the shared list")
+	}
+}
+
+// TestDomainListsGet tests the local disposable and free checks in Get
+func TestDomainListsGet(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		emailAddress := req.URL.Query().Get("emailAddress")
+		domain := emailAddress[strings.LastIndexByte(emailAddress, '@')+1:]
+		_, _ = w.Write([]byte(`{"emailAddress":"` + emailAddress + `","domain":"` + domain +
+			`","formatCheck":"true","smtpCheck":"true","freeCheck":"false"}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lists := DefaultDomainLists()
+	lists.Disposable.Add("burner.example")
+
+	api := NewClient(apiKey, ClientParams{
+		HTTPClient:   server.Client(),
+		EvapiBaseURL: apiURL,
+		DomainLists:  lists,
+	})
+
+	ctx := context.Background()
+
+	evapiResp, resp, err := api.Get(ctx, "john@gmail.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evapiResp.FreeCheck == nil || !bool(*evapiResp.FreeCheck) || evapiResp.DisposableCheck != nil || resp.Synthesized {
+		t.Errorf("Get() = %+v, expected the free check set and the disposable check not set", evapiResp)
+	}
+
+	evapiResp, _, err = api.Get(ctx, "john@mail.burner.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evapiResp.DisposableCheck == nil || !bool(*evapiResp.DisposableCheck) {
+		t.Errorf("Get() = %+v, expected the disposable check set", evapiResp)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+
+	lists.SkipDisposable = true
+
+	evapiResp, resp, err = api.Get(ctx, "john@Mailinator.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Synthesized || evapiResp.DisposableCheck == nil || !bool(*evapiResp.DisposableCheck) ||
+		evapiResp.FormatCheck == nil || !bool(*evapiResp.FormatCheck) || evapiResp.SmtpCheck != nil ||
+		evapiResp.Domain != "mailinator.com" {
+		t.Errorf("Get() = %+v, %v, expected synthesized disposable response", evapiResp, resp)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
@@ -0,0 +1,105 @@
+package emailverifier
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Errors reported by ErrorResponse for the corresponding classes of status codes.
+// Use errors.Is to check the class of the error
+var (
+	// ErrAuthentication means the API key is missing, invalid or has no access to the API
+	ErrAuthentication = errors.New("authentication failed")
+
+	// ErrInsufficientCredits means the account has run out of credits or exceeded its quota
+	ErrInsufficientCredits = errors.New("insufficient credits")
+
+	// ErrRateLimited means the request rate limit is exceeded
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrBadRequest means the request parameters are invalid
+	ErrBadRequest = errors.New("bad request")
+
+	// ErrServerError means the API failed to process a valid request
+	ErrServerError = errors.New("server error")
+)
+
+// ErrorResponse is returned when the response status code is not 2xx
+type ErrorResponse struct {
+	Response *http.Response
+	Message  string
+
+	// StatusCode is the response status code
+	StatusCode int
+
+	// ErrorMessage is the error message from the response body, it's nil if the body can't be parsed
+	ErrorMessage *ErrorMessage
+
+	// RetryAfter is the delay requested by the server with the Retry-After header
+	RetryAfter time.Duration
+}
+
+// Error returns error message as a string
+func (e ErrorResponse) Error() string {
+	if e.Message != "" {
+		return "API failed with status code: " + strconv.Itoa(e.StatusCode) + " (" + e.Message + ")"
+	}
+	return "API failed with status code: " + strconv.Itoa(e.StatusCode)
+}
+
+// Is reports whether the error belongs to the class of target, one of ErrAuthentication, ErrInsufficientCredits,
+// ErrRateLimited, ErrBadRequest or ErrServerError
+func (e ErrorResponse) Is(target error) bool {
+	kind := e.kind()
+	return kind != nil && kind == target
+}
+
+// Unwrap returns the error message from the response body if any
+func (e ErrorResponse) Unwrap() error {
+	if e.ErrorMessage == nil {
+		return nil
+	}
+	return *e.ErrorMessage
+}
+
+// kind returns the class of the error based on the status code
+func (e ErrorResponse) kind() error {
+	switch c := e.StatusCode; {
+	case c == http.StatusUnauthorized || c == http.StatusForbidden:
+		return ErrAuthentication
+	case c == http.StatusPaymentRequired:
+		return ErrInsufficientCredits
+	case c == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case c >= 500:
+		return ErrServerError
+	case c >= 400:
+		return ErrBadRequest
+	}
+	return nil
+}
+
+// checkResponse checks if the response status code is not 2xx
+func checkResponse(r *Response) error {
+	if c := r.StatusCode; c >= 200 && c <= 299 {
+		return nil
+	}
+
+	var errorResponse = ErrorResponse{
+		Response:   r.Response,
+		StatusCode: r.StatusCode,
+	}
+
+	if body, err := parse(r.Body, detectFormat(r.Body)); err == nil && body.ErrorMessage != nil {
+		errorResponse.ErrorMessage = body.ErrorMessage
+		errorResponse.Message = body.ErrorMessage.Message
+	}
+
+	if retryAfter, ok := parseRetryAfter(r.Header.Get("Retry-After"), time.Now()); ok {
+		errorResponse.RetryAfter = retryAfter
+	}
+
+	return errorResponse
+}
@@ -0,0 +1,77 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestErrorResponse tests classification of the API errors returned by Get and GetRaw
+func TestErrorResponse(t *testing.T) {
+
+	const errResp = `{"ErrorMessage":{"Error":"test error message"}}`
+
+	tests := []struct {
+		status      int
+		body        string
+		retryAfter  string
+		want        error
+		wantMessage string
+		wantRetry   time.Duration
+	}{
+		{status: http.StatusUnauthorized, body: errResp, want: ErrAuthentication, wantMessage: "test error message"},
+		{status: http.StatusForbidden, body: errResp, want: ErrAuthentication, wantMessage: "test error message"},
+		{status: http.StatusPaymentRequired, body: errResp, want: ErrInsufficientCredits, wantMessage: "test error message"},
+		{status: http.StatusTooManyRequests, body: "", retryAfter: "30", want: ErrRateLimited, wantRetry: 30 * time.Second},
+		{status: http.StatusBadRequest, body: errResp, want: ErrBadRequest, wantMessage: "test error message"},
+		{status: http.StatusUnprocessableEntity, body: "<>", want: ErrBadRequest},
+		{status: http.StatusInternalServerError, body: "<>", want: ErrServerError},
+		{status: http.StatusServiceUnavailable, body: errResp, want: ErrServerError, wantMessage: "test error message"},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.status), func(t *testing.T) {
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			api := newAPI(server, "")
+
+			_, resp, errGet := api.Get(context.Background(), "support@whoisxmlapi.com")
+			if resp == nil || resp.StatusCode != tt.status {
+				t.Errorf("Evapi.Get() response = %v, want status code %d", resp, tt.status)
+			}
+
+			_, errGetRaw := api.GetRaw(context.Background(), "support@whoisxmlapi.com")
+
+			for _, err := range []error{errGet, errGetRaw} {
+				if !errors.Is(err, tt.want) {
+					t.Errorf("error = %v, want %v", err, tt.want)
+				}
+
+				var errResponse ErrorResponse
+				if !errors.As(err, &errResponse) {
+					t.Fatalf("error = %T, want ErrorResponse", err)
+				}
+				if errResponse.StatusCode != tt.status || errResponse.RetryAfter != tt.wantRetry {
+					t.Errorf("error = %+v, want status code %d and retry after %v",
+						errResponse, tt.status, tt.wantRetry)
+				}
+
+				var errMessage ErrorMessage
+				if errors.As(err, &errMessage) != (tt.wantMessage != "") || errMessage.Message != tt.wantMessage {
+					t.Errorf("error message = %q, want %q", errMessage.Message, tt.wantMessage)
+				}
+			}
+		})
+	}
+}
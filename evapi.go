@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // EvapiService is an interface for Email Verification API
@@ -16,6 +21,9 @@ type EvapiService interface {
 
 	// GetRaw returns raw Email Verification API response as Response struct with Body saved as a byte slice
 	GetRaw(ctx context.Context, emailAddress string, opts ...Option) (*Response, error)
+
+	// GetMany returns parsed Email Verification API responses for several email addresses in the input order
+	GetMany(ctx context.Context, emailAddresses []string, opts ...Option) []BatchResult
 }
 
 // Response is the http.Response wrapper with Body saved as a byte slice
@@ -24,6 +32,51 @@ type Response struct {
 
 	//Body is the byte slice representation of http.Response Body
 	Body []byte
+
+	// Attempts is the number of attempts made to get the response
+	Attempts int
+
+	// CacheHit indicates that the response is taken from the cache and no API credit was spent
+	CacheHit bool
+
+	// Synthesized indicates that the response is produced locally without an API request
+	Synthesized bool
+
+	// Address is the normalized email address, it's nil if the address can't be normalized
+	Address *Address
+}
+
+// NewResponse returns Response with the status code and the body, e.g. for EvapiService implementations in tests
+func NewResponse(statusCode int, body []byte) *Response {
+	return &Response{
+		Response: &http.Response{
+			Status:     strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+			StatusCode: statusCode,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{},
+		},
+		Body: body,
+	}
+}
+
+// localResponse returns the successful Response with the body which is not received from the API
+func localResponse(body []byte) *Response {
+	return NewResponse(http.StatusOK, body)
+}
+
+// synthesize returns the Email Verification API response produced locally without an API request
+func synthesize(evapiResp *EvapiResponse) (*EvapiResponse, *Response, error) {
+	body, err := json.Marshal(evapiResp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot encode response: %w", err)
+	}
+
+	resp := localResponse(body)
+	resp.Synthesized = true
+
+	return evapiResp, resp, nil
 }
 
 // emailVerifierServiceOp is the type implementing the EvapiService interface
@@ -34,7 +87,7 @@ type emailVerifierServiceOp struct {
 
 var _ EvapiService = &emailVerifierServiceOp{}
 
-// newRequest creates the API request with default parameters and the specified apiKey
+// newRequest creates the API request with default parameters and the API key
 func (service *emailVerifierServiceOp) newRequest() (*http.Request, error) {
 
 	req, err := service.client.NewRequest(http.MethodGet, service.baseURL, nil)
@@ -42,18 +95,24 @@ func (service *emailVerifierServiceOp) newRequest() (*http.Request, error) {
 		return nil, err
 	}
 
-	query := url.Values{}
-	query.Set("apiKey", service.client.apiKey)
-
-	req.URL.RawQuery = query.Encode()
+	service.client.authorize(req)
 
 	return req, nil
 }
 
 // apiResponse is used for parsing Email Verification API response as a model instance
 type apiResponse struct {
+	XMLName xml.Name `json:"-"`
 	EvapiResponse
-	ErrorMessage *ErrorMessage `json:"ErrorMessage"`
+	ErrorMessage *ErrorMessage `json:"ErrorMessage" xml:"ErrorMessage"`
+
+	// XMLMxRecords is the mail servers list in XML where items may have any element name
+	XMLMxRecords struct {
+		Items []string `xml:",any"`
+	} `json:"-" xml:"mxRecords"`
+
+	// XMLError is the error message in XML where ErrorMessage is the root element
+	XMLError string `json:"-" xml:"Error"`
 }
 
 // request returns intermediate EVAPI response for further actions
@@ -62,13 +121,15 @@ func (service *emailVerifierServiceOp) request(ctx context.Context, emailAddress
 		return nil, &ArgError{"emailAddress", "cannot be empty"}
 	}
 
+	address := normalize(emailAddress)
+
 	req, err := service.newRequest()
 	if err != nil {
 		return nil, err
 	}
 
 	q := req.URL.Query()
-	q.Set("emailAddress", emailAddress)
+	q.Set("emailAddress", queryAddress(emailAddress, address))
 
 	for _, opt := range opts {
 		opt(q)
@@ -76,64 +137,250 @@ func (service *emailVerifierServiceOp) request(ctx context.Context, emailAddress
 
 	req.URL.RawQuery = q.Encode()
 
-	var b bytes.Buffer
-	resp, err := service.client.Do(ctx, req, &b)
-	if err != nil {
-		return &Response{
-			Response: resp,
-			Body:     b.Bytes(),
-		}, err
+	resp, err := service.client.do(ctx, req)
+	if resp != nil {
+		resp.Address = address
 	}
 
-	return &Response{
-		Response: resp,
-		Body:     b.Bytes(),
-	}, nil
+	return resp, err
 }
 
-// parse parses raw Email Verification API response
-func parse(raw []byte) (*apiResponse, error) {
+// parse parses raw Email Verification API response in the specified format: JSON | XML
+func parse(raw []byte, format string) (*apiResponse, error) {
 
 	var response apiResponse
 
-	err := json.NewDecoder(bytes.NewReader(raw)).Decode(&response)
+	if !strings.EqualFold(format, "XML") {
+		err := json.NewDecoder(bytes.NewReader(raw)).Decode(&response)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse response: %w", err)
+		}
+
+		return &response, nil
+	}
+
+	err := xml.NewDecoder(bytes.NewReader(raw)).Decode(&response)
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse response: %w", err)
 	}
 
+	response.MxRecords = response.XMLMxRecords.Items
+
+	if response.XMLName.Local == "ErrorMessage" {
+		response.ErrorMessage = &ErrorMessage{Message: response.XMLError}
+	}
+
 	return &response, nil
 }
 
+// detectFormat returns the format of raw Email Verification API response: JSON | XML
+func detectFormat(raw []byte) string {
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("<")) {
+		return "XML"
+	}
+	return "JSON"
+}
+
+// withOutputFormat returns the options with the JSON response format which can be overridden by them
+func withOutputFormat(opts []Option) []Option {
+	optsFormat := make([]Option, 0, len(opts)+1)
+	optsFormat = append(optsFormat, OptionOutputFormat("JSON"))
+	return append(optsFormat, opts...)
+}
+
+// outputFormat returns the response format requested by the options
+func outputFormat(opts []Option) string {
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+	return query.Get("outputFormat")
+}
+
 // Get returns parsed Email Verification API response
+// The response is requested in JSON format unless another one is set with OptionOutputFormat
 func (service emailVerifierServiceOp) Get(
 	ctx context.Context,
 	emailAddress string,
 	opts ...Option,
 ) (evapiResponse *EvapiResponse, resp *Response, err error) {
 
-	optsJson := make([]Option, 0, len(opts)+1)
-	optsJson = append(optsJson, opts...)
-	optsJson = append(optsJson, OptionOutputFormat("JSON"))
+	ctx, finish := service.client.instrument(ctx, "Get", emailAddress, opts)
+	defer func() {
+		finish(resp, err)
+	}()
 
-	resp, err = service.request(ctx, emailAddress, optsJson...)
-	if err != nil {
-		return nil, resp, err
+	coalescer := service.client.coalescer
+	if coalescer == nil {
+		return service.get(ctx, emailAddress, opts...)
+	}
+
+	key, refresh := cacheKey(queryAddress(emailAddress, normalize(emailAddress)), withOutputFormat(opts))
+	if refresh {
+		key += "#refresh"
 	}
 
-	evapiResp, err := parse(resp.Body)
+	return coalescer.do(ctx, key, func(ctx context.Context) (*EvapiResponse, *Response, error) {
+		return service.get(ctx, emailAddress, opts...)
+	})
+}
+
+// get returns parsed Email Verification API response
+func (service emailVerifierServiceOp) get(
+	ctx context.Context,
+	emailAddress string,
+	opts ...Option,
+) (evapiResponse *EvapiResponse, resp *Response, err error) {
+
+	address := normalize(emailAddress)
+
+	if service.client.validateSyntax && emailAddress != "" &&
+		(address == nil || ValidateAddress(address.ASCII()) != nil) {
+		return service.synthesize(invalidFormat(emailAddress), address)
+	}
+
+	lists := service.client.domainLists
+	if lists != nil {
+		if local, ok := lists.skip(emailAddress, address); ok {
+			return service.synthesize(local, address)
+		}
+	}
+
+	optsFormat := withOutputFormat(opts)
+
+	cache := service.client.cache
+	key, refresh := cacheKey(queryAddress(emailAddress, address), optsFormat)
+
+	var entry *CacheEntry
+	if cache != nil && !refresh {
+		entry, _ = cache.Get(key)
+	}
+
+	var mxRecords []string
+	var mxErr error
+
+	checker := service.client.dnsChecker
+	checkDNS := checker != nil && entry == nil && address != nil && !strings.HasPrefix(address.Domain, "[")
+
+	// Without ShortCircuit the lookup is needed only if the API doesn't check DNS, so it's made after the request
+	if checkDNS && checker.ShortCircuit {
+		mxRecords, mxErr = checker.LookupMX(ctx, address.Domain)
+		if errors.Is(mxErr, ErrNoMailExchanger) {
+			return service.synthesize(noMailExchanger(emailAddress, address), address)
+		}
+	}
+
+	if entry != nil {
+		resp = cachedResponse(entry)
+		resp.Address = address
+	} else {
+		resp, err = service.request(ctx, emailAddress, optsFormat...)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		if err = checkResponse(resp); err != nil {
+			return nil, resp, err
+		}
+	}
+
+	evapiResp, err := parse(resp.Body, outputFormat(optsFormat))
 	if err != nil {
 		return nil, resp, err
 	}
 
 	if evapiResp.ErrorMessage != nil {
-		return nil, nil, ErrorMessage{
+		return nil, resp, ErrorMessage{
 			evapiResp.ErrorMessage.Message,
 		}
 	}
 
+	if cache != nil && entry == nil {
+		// The cache keeps its own copy of the body which is returned to the caller
+		body := append([]byte(nil), resp.Body...)
+		cache.Set(key, &CacheEntry{Body: body, FetchedAt: time.Now(), Audit: evapiResp.Audit})
+	}
+
+	if checkDNS && evapiResp.DnsCheck == nil && !checker.ShortCircuit {
+		mxRecords, mxErr = checker.LookupMX(ctx, address.Domain)
+	}
+
+	if checkDNS && evapiResp.DnsCheck == nil && (mxErr == nil || errors.Is(mxErr, ErrNoMailExchanger)) {
+		dnsCheck := StringBool(mxErr == nil)
+		evapiResp.DnsCheck = &dnsCheck
+		evapiResp.MxRecords = mxRecords
+	}
+
+	if lists != nil {
+		lists.Prefill(&evapiResp.EvapiResponse)
+	}
+
+	if service.client.suggester != nil {
+		service.client.suggester.attach(&evapiResp.EvapiResponse)
+	}
+
 	return &evapiResp.EvapiResponse, resp, nil
 }
 
+// synthesize returns the response produced locally for the normalized address with the suggestion attached
+func (service emailVerifierServiceOp) synthesize(
+	evapiResp *EvapiResponse,
+	address *Address,
+) (*EvapiResponse, *Response, error) {
+
+	evapiResp, resp, err := synthesize(evapiResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	resp.Address = address
+	if service.client.suggester != nil {
+		service.client.suggester.attach(evapiResp)
+	}
+
+	return evapiResp, resp, nil
+}
+
+// invalidFormat returns the Email Verification API response for the address with invalid syntax
+func invalidFormat(emailAddress string) *EvapiResponse {
+	formatCheck := StringBool(false)
+
+	evapiResp := &EvapiResponse{
+		EmailAddress: emailAddress,
+		FormatCheck:  &formatCheck,
+	}
+
+	if i := strings.LastIndexByte(emailAddress, '@'); i >= 0 {
+		evapiResp.Username, evapiResp.Domain = emailAddress[:i], emailAddress[i+1:]
+	} else {
+		evapiResp.Username = emailAddress
+	}
+
+	return evapiResp
+}
+
+// checkedLocally returns the Email Verification API response for the address checked without an API request
+func checkedLocally(emailAddress string, address *Address) *EvapiResponse {
+	formatCheck := StringBool(ValidateAddress(address.ASCII()) == nil)
+
+	return &EvapiResponse{
+		Username:     address.LocalPart,
+		Domain:       address.Domain,
+		EmailAddress: emailAddress,
+		FormatCheck:  &formatCheck,
+	}
+}
+
+// noMailExchanger returns the Email Verification API response for the address without a mail exchanger
+func noMailExchanger(emailAddress string, address *Address) *EvapiResponse {
+	evapiResp := checkedLocally(emailAddress, address)
+
+	dnsCheck := StringBool(false)
+	evapiResp.DnsCheck = &dnsCheck
+
+	return evapiResp
+}
+
 // GetRaw returns raw Email Verification API response as Response struct with Body saved as a byte slice
 func (service emailVerifierServiceOp) GetRaw(
 	ctx context.Context,
@@ -141,12 +388,17 @@ func (service emailVerifierServiceOp) GetRaw(
 	opts ...Option,
 ) (resp *Response, err error) {
 
+	ctx, finish := service.client.instrument(ctx, "GetRaw", name, opts)
+	defer func() {
+		finish(resp, err)
+	}()
+
 	resp, err = service.request(ctx, name, opts...)
 	if err != nil {
 		return resp, err
 	}
 
-	if respErr := checkResponse(resp.Response); respErr != nil {
+	if respErr := checkResponse(resp); respErr != nil {
 		return resp, respErr
 	}
 
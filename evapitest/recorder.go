@@ -0,0 +1,320 @@
+package evapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redacted replaces the API key in the fixtures
+const redacted = "REDACTED"
+
+// apiKeyBodyPattern matches the API key in JSON request bodies
+var apiKeyBodyPattern = regexp.MustCompile(`("apiKey"\s*:\s*)"[^"]*"`)
+
+// ErrNoFixture is returned by Recorder in the replay mode for requests without a recorded interaction
+var ErrNoFixture = errors.New("no recorded interaction matches the request")
+
+// Mode is the Recorder mode
+type Mode int
+
+const (
+	// ModeReplay serves the recorded interactions without network access
+	ModeReplay Mode = iota
+
+	// ModeRecord sends the requests and records the interactions
+	ModeRecord
+)
+
+// Interaction is the recorded request and response pair
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the recorded request with the API key replaced by REDACTED
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is the recorded response
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Recorder is the http.RoundTripper which records the interactions to the fixture file or replays them.
+// Requests match the recorded ones by the method, the URL without the API key regardless of the query parameters
+// order and the JSON body without the API key. Each recorded interaction is replayed once in the recorded order
+//
+//	mode := evapitest.ModeReplay
+//	if os.Getenv("RECORD") != "" {
+//		mode = evapitest.ModeRecord
+//	}
+//	recorder, err := evapitest.NewRecorder("testdata/get.json", mode)
+//	...
+//	client := emailverifier.NewClient(apiKey, emailverifier.ClientParams{HTTPClient: recorder.Client()})
+type Recorder struct {
+	// Transport sends the requests in the record mode. If it's nil then http.DefaultTransport is used
+	Transport http.RoundTripper
+
+	name string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	unmatched    []string
+}
+
+var _ http.RoundTripper = &Recorder{}
+
+// NewRecorder creates Recorder for the fixture file
+// In the replay mode the file is read, in the record mode it's overwritten after every recorded interaction
+func NewRecorder(name string, mode Mode) (*Recorder, error) {
+	r := &Recorder{name: name, mode: mode}
+
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read fixture: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("cannot parse fixture %s: %w", name, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+
+	return r, nil
+}
+
+// Client returns the HTTP client using the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Unmatched returns the descriptions of the requests which didn't match any recorded interaction
+func (r *Recorder) Unmatched() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.unmatched...)
+}
+
+// Unused returns the recorded interactions which were not replayed
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.interactions[i])
+		}
+	}
+	return unused
+}
+
+// RoundTrip records or replays the interaction
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, req, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, recorded)
+	}
+
+	return r.replay(req, recorded)
+}
+
+// record sends the request and saves the interaction
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	header.Del("Date")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = append(r.interactions, Interaction{
+		Request:  recorded,
+		Response: RecordedResponse{StatusCode: resp.StatusCode, Header: header, Body: string(body)},
+	})
+	r.used = append(r.used, true)
+
+	if err = r.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// save writes the interactions to the fixture file
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode fixture: %w", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(r.name), 0755); err != nil {
+		return fmt.Errorf("cannot write fixture: %w", err)
+	}
+
+	tmp := r.name + ".tmp"
+	if err = os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("cannot write fixture: %w", err)
+	}
+
+	if err = os.Rename(tmp, r.name); err != nil {
+		return fmt.Errorf("cannot write fixture: %w", err)
+	}
+
+	return nil
+}
+
+// replay returns the response of the first unused matching interaction
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.interactions {
+		if r.used[i] || !matchRequest(r.interactions[i].Request, recorded) {
+			continue
+		}
+
+		r.used[i] = true
+		return replayResponse(req, r.interactions[i].Response), nil
+	}
+
+	description := recorded.Method + " " + recorded.URL
+	r.unmatched = append(r.unmatched, description)
+
+	return nil, fmt.Errorf("%w in %s: %s", ErrNoFixture, r.name, description)
+}
+
+// recordRequest returns the request with the API key replaced and the request to be sent
+func recordRequest(req *http.Request) (RecordedRequest, *http.Request, error) {
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    scrubURL(req.URL),
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return recorded, nil, fmt.Errorf("cannot read request body: %w", err)
+		}
+		// The request must not be modified, so the clone with the read body is sent
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		recorded.Body = apiKeyBodyPattern.ReplaceAllString(string(body), `${1}"`+redacted+`"`)
+	}
+
+	return recorded, req, nil
+}
+
+// scrubURL returns the URL with the API key replaced
+func scrubURL(u *url.URL) string {
+	scrubbed := *u
+
+	query := scrubbed.Query()
+	if query.Get("apiKey") != "" {
+		query.Set("apiKey", redacted)
+	}
+	scrubbed.RawQuery = query.Encode()
+
+	return scrubbed.String()
+}
+
+// matchRequest reports whether the requests have the same method, URL and body
+// regardless of the query parameters order and the JSON fields order
+func matchRequest(a, b RecordedRequest) bool {
+	if a.Method != b.Method {
+		return false
+	}
+
+	ua, err := url.Parse(a.URL)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b.URL)
+	if err != nil {
+		return false
+	}
+
+	// The API key may be sent in the query or in the header, so it's ignored
+	qa, qb := ua.Query(), ub.Query()
+	qa.Del("apiKey")
+	qb.Del("apiKey")
+	ua.RawQuery, ub.RawQuery = "", ""
+	if ua.String() != ub.String() || !reflect.DeepEqual(qa, qb) {
+		return false
+	}
+
+	if a.Body == b.Body {
+		return true
+	}
+
+	var ja, jb interface{}
+	if json.Unmarshal([]byte(a.Body), &ja) != nil || json.Unmarshal([]byte(b.Body), &jb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(ja, jb)
+}
+
+// replayResponse returns the recorded response for the request
+func replayResponse(req *http.Request, recorded RecordedResponse) *http.Response {
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,130 @@
+package evapitest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	emailverifier "github.com/whois-api-llc/go-email-verifier"
+)
+
+// TestRecorder tests recording and replaying the interactions
+func TestRecorder(t *testing.T) {
+	const apiKey = "at_secret_key"
+
+	server := NewServer()
+	server.HandleAddress("error@example.com", Error(http.StatusBadRequest, "bad address"))
+
+	fixture := filepath.Join(t.TempDir(), "testdata", "fixture.json")
+
+	recorder, err := NewRecorder(fixture, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.Transport = server.Client().Transport
+
+	ctx := context.Background()
+	params := emailverifier.ClientParams{
+		HTTPClient:   recorder.Client(),
+		EvapiBaseURL: server.URL(),
+		BulkBaseURL:  server.URL(),
+	}
+
+	recorded := emailverifier.NewClient(apiKey, params)
+	want, _, err := recorded.Get(ctx, "john@example.com", emailverifier.OptionCheckFree(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = recorded.Get(ctx, "error@example.com"); err == nil {
+		t.Fatal("Get() expected error")
+	}
+	_, _, _ = recorded.BulkService.Status(ctx, 1)
+
+	server.Close()
+
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), apiKey) || !strings.Contains(string(data), "REDACTED") {
+		t.Errorf("fixture contains the API key:\n%s", data)
+	}
+
+	replayer, err := NewRecorder(fixture, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params.HTTPClient = replayer.Client()
+	params.APIKeyLocation = emailverifier.APIKeyInHeader
+
+	replayed := emailverifier.NewClient("at_other_key", params)
+
+	got, _, err := replayed.Get(ctx, "john@example.com", emailverifier.OptionCheckFree(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.EmailAddress != want.EmailAddress || got.FreeCheck != nil || got.SmtpCheck == nil {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+
+	_, _, err = replayed.Get(ctx, "error@example.com")
+	if !errors.Is(err, emailverifier.ErrBadRequest) {
+		t.Errorf("Get() error = %v, want %v", err, emailverifier.ErrBadRequest)
+	}
+
+	if len(replayer.Unused()) != 1 {
+		t.Errorf("Unused() = %v, expected the bulk request", replayer.Unused())
+	}
+	_, _, _ = replayed.BulkService.Status(ctx, 1)
+	if len(replayer.Unused()) != 0 {
+		t.Errorf("Unused() = %v, expected none", replayer.Unused())
+	}
+
+	_, _, err = replayed.Get(ctx, "john@example.com", emailverifier.OptionCheckFree(0))
+	if !errors.Is(err, ErrNoFixture) || len(replayer.Unmatched()) != 1 {
+		t.Errorf("Get() error = %v, want %v", err, ErrNoFixture)
+	}
+}
+
+// TestMatchRequest tests matching of the requests
+func TestMatchRequest(t *testing.T) {
+	tests := []struct {
+		a, b RecordedRequest
+		want bool
+	}{
+		{
+			a:    RecordedRequest{"GET", "https://example.com/api?apiKey=REDACTED&b=2&a=1", ""},
+			b:    RecordedRequest{"GET", "https://example.com/api?a=1&b=2", ""},
+			want: true,
+		},
+		{
+			a:    RecordedRequest{"GET", "https://example.com/api?a=1", ""},
+			b:    RecordedRequest{"GET", "https://example.com/api?a=2", ""},
+			want: false,
+		},
+		{
+			a:    RecordedRequest{"GET", "https://example.com/api?a=1", ""},
+			b:    RecordedRequest{"POST", "https://example.com/api?a=1", ""},
+			want: false,
+		},
+		{
+			a:    RecordedRequest{"POST", "https://example.com/api", `{"apiKey":"REDACTED","ids":[1]}`},
+			b:    RecordedRequest{"POST", "https://example.com/api", `{"ids": [1], "apiKey": "REDACTED"}`},
+			want: true,
+		},
+		{
+			a:    RecordedRequest{"POST", "https://example.com/api", `{"ids":[1]}`},
+			b:    RecordedRequest{"POST", "https://example.com/api", `{"ids":[2]}`},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		if got := matchRequest(tt.a, tt.b); got != tt.want {
+			t.Errorf("matchRequest(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+// Package evapitest provides a scriptable fake Email Verification API server for tests.
+//
+// The server answers every address with a deliverable response by default. Canned responses, errors,
+// latency, truncated bodies and rate limits can be set per address, per domain or for all requests:
+//
+//	server := evapitest.NewServer()
+//	defer server.Close()
+//
+//	server.HandleDomain("mailinator.com", evapitest.Respond(&emailverifier.EvapiResponse{...}))
+//	server.HandleAddress("down@example.com", evapitest.Error(http.StatusServiceUnavailable, "maintenance"))
+//
+//	client := emailverifier.NewClient("at_test", emailverifier.ClientParams{
+//		HTTPClient:   server.Client(),
+//		EvapiBaseURL: server.URL(),
+//	})
+//
+// Service is the in-memory EvapiService scripted the same way for unit tests without HTTP,
+// and Recorder records real API interactions to fixture files and replays them offline.
+package evapitest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	emailverifier "github.com/whois-api-llc/go-email-verifier"
+)
+
+// Reply is the scripted response of the server
+type Reply struct {
+	// Response is the response returned in JSON or XML as requested. If it's nil then the default response is used
+	Response *emailverifier.EvapiResponse
+
+	// StatusCode is the response status code. Default: 200.
+	StatusCode int
+
+	// ErrorMessage is returned in the {"ErrorMessage":{"Error":...}} body instead of the response if it's not empty
+	ErrorMessage string
+
+	// Body is returned as is instead of the response if it's not nil
+	Body []byte
+
+	// Header is added to the response headers
+	Header http.Header
+
+	// Latency is the delay before the response. The request context cancellation stops waiting
+	Latency time.Duration
+
+	// Truncate is the number of bytes cut from the end of the body while Content-Length is left intact
+	Truncate int
+
+	// Err is returned by Service instead of the response. It's ignored by Server
+	Err error
+
+	// Times is the number of requests the reply is used for, after that the next reply is used.
+	// Zero means that the reply is used for all requests
+	Times int
+}
+
+// Respond returns the reply with the response
+func Respond(response *emailverifier.EvapiResponse) Reply {
+	return Reply{Response: response}
+}
+
+// Error returns the reply with the status code and the error message in the body
+func Error(statusCode int, message string) Reply {
+	return Reply{StatusCode: statusCode, ErrorMessage: message}
+}
+
+// Server is the fake Email Verification API server
+type Server struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	addresses map[string][]*Reply
+	domains   map[string][]*Reply
+	fallback  []*Reply
+	queries   []url.Values
+	apiKey    string
+
+	rateLimit   int
+	rateWindow  time.Duration
+	windowStart time.Time
+	windowCount int
+}
+
+// NewServer starts the fake server. It must be closed with Close
+func NewServer() *Server {
+	s := &Server{
+		addresses: make(map[string][]*Reply),
+		domains:   make(map[string][]*Reply),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns the server URL to be used as ClientParams.EvapiBaseURL
+func (s *Server) URL() *url.URL {
+	u, err := url.Parse(s.server.URL)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// Client returns the HTTP client configured for the server
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// HandleAddress adds the reply for the email address. The domain is case-insensitive
+func (s *Server) HandleAddress(emailAddress string, reply Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := addressKey(emailAddress)
+	s.addresses[key] = append(s.addresses[key], &reply)
+}
+
+// HandleDomain adds the reply for all addresses in the domain
+func (s *Server) HandleDomain(domain string, reply Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(strings.TrimSuffix(domain, "."))
+	s.domains[key] = append(s.domains[key], &reply)
+}
+
+// HandleDefault adds the reply for the addresses without replies set by HandleAddress and HandleDomain
+func (s *Server) HandleDefault(reply Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fallback = append(s.fallback, &reply)
+}
+
+// RequireAPIKey makes the server reply 401 to requests without the API key
+// in the apiKey parameter or the X-Authentication-Token header
+func (s *Server) RequireAPIKey(apiKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.apiKey = apiKey
+}
+
+// RateLimit makes the server reply 429 with the Retry-After header to the requests exceeding
+// the limit within the window. Zero limit disables it
+func (s *Server) RateLimit(limit int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rateLimit, s.rateWindow = limit, window
+	s.windowStart, s.windowCount = time.Time{}, 0
+}
+
+// Queries returns the query parameters of the received requests in order, the API key is removed
+func (s *Server) Queries() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queries := make([]url.Values, len(s.queries))
+	for i, q := range s.queries {
+		queries[i] = cloneValues(q)
+	}
+	return queries
+}
+
+// LastQuery returns the query parameters of the last received request or nil if there are none
+func (s *Server) LastQuery() url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queries) == 0 {
+		return nil
+	}
+	return cloneValues(s.queries[len(s.queries)-1])
+}
+
+// Requests returns the number of the received requests
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.queries)
+}
+
+// Reset removes the replies, the recorded queries and the rate limit
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addresses = make(map[string][]*Reply)
+	s.domains = make(map[string][]*Reply)
+	s.fallback = nil
+	s.queries = nil
+	s.apiKey = ""
+	s.rateLimit, s.rateWindow = 0, 0
+	s.windowStart, s.windowCount = time.Time{}, 0
+}
+
+// serveHTTP handles the Email Verification API request
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	apiKey := query.Get("apiKey")
+	if apiKey == "" {
+		apiKey = req.Header.Get("X-Authentication-Token")
+	}
+	query.Del("apiKey")
+
+	emailAddress := query.Get("emailAddress")
+	xmlFormat := strings.EqualFold(query.Get("outputFormat"), "XML")
+
+	reply, limited := s.record(query, apiKey)
+	switch {
+	case limited > 0:
+		w.Header().Set("Retry-After", strconv.Itoa(int((limited+time.Second-1)/time.Second)))
+		rateLimited := Error(http.StatusTooManyRequests, "Rate limit exceeded")
+		reply = &rateLimited
+	case reply == nil:
+		unauthorized := Error(http.StatusUnauthorized, "Access restricted. Enter the correct API key.")
+		reply = &unauthorized
+	}
+
+	if reply.Latency > 0 {
+		timer := time.NewTimer(reply.Latency)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return
+		}
+	}
+
+	body := reply.Body
+	if body == nil {
+		body = encode(reply, emailAddress, query, xmlFormat)
+	}
+
+	for k, v := range reply.Header {
+		w.Header()[k] = v
+	}
+	if xmlFormat && reply.Body == nil {
+		w.Header().Set("Content-Type", "application/xml")
+	} else if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	if reply.Truncate > 0 {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		if reply.Truncate < len(body) {
+			body = body[:len(body)-reply.Truncate]
+		} else {
+			body = nil
+		}
+	}
+
+	statusCode := reply.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
+
+// record saves the query and returns the reply for it, nil if the API key is wrong,
+// or the delay until the end of the rate limit window if the request is rate limited
+func (s *Server) record(query url.Values, apiKey string) (*Reply, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queries = append(s.queries, query)
+
+	if s.rateLimit > 0 {
+		now := time.Now()
+		if now.Sub(s.windowStart) >= s.rateWindow {
+			s.windowStart, s.windowCount = now, 0
+		}
+		s.windowCount++
+		if s.windowCount > s.rateLimit {
+			return nil, s.windowStart.Add(s.rateWindow).Sub(now)
+		}
+	}
+
+	if s.apiKey != "" && apiKey != s.apiKey {
+		return nil, 0
+	}
+
+	emailAddress := query.Get("emailAddress")
+
+	if reply := next(s.addresses, addressKey(emailAddress)); reply != nil {
+		return reply, 0
+	}
+
+	if i := strings.LastIndexByte(emailAddress, '@'); i >= 0 {
+		if reply := next(s.domains, strings.ToLower(emailAddress[i+1:])); reply != nil {
+			return reply, 0
+		}
+	}
+
+	if reply := nextReply(&s.fallback); reply != nil {
+		return reply, 0
+	}
+
+	return &Reply{}, 0
+}
+
+// next returns the next reply for the key
+func next(replies map[string][]*Reply, key string) *Reply {
+	list := replies[key]
+	reply := nextReply(&list)
+	replies[key] = list
+	return reply
+}
+
+// nextReply returns the first reply in the list and removes it if it's used up
+func nextReply(list *[]*Reply) *Reply {
+	if len(*list) == 0 {
+		return nil
+	}
+
+	reply := (*list)[0]
+	if reply.Times > 0 {
+		reply.Times--
+		if reply.Times == 0 {
+			*list = (*list)[1:]
+		}
+		replyCopy := *reply
+		return &replyCopy
+	}
+
+	return reply
+}
+
+// apiResponse is the response in XML
+type apiResponse struct {
+	XMLName xml.Name `xml:"ApiResponse"`
+	*emailverifier.EvapiResponse
+}
+
+// errorMessage is the error message body
+type errorMessage struct {
+	XMLName      xml.Name                    `json:"-" xml:"ErrorMessage"`
+	ErrorMessage *emailverifier.ErrorMessage `json:"ErrorMessage" xml:"-"`
+	Error        string                      `json:"-" xml:"Error"`
+}
+
+// encode returns the body of the reply in JSON or XML
+func encode(reply *Reply, emailAddress string, query url.Values, xmlFormat bool) []byte {
+	var v interface{}
+
+	if reply.ErrorMessage != "" {
+		v = errorMessage{
+			ErrorMessage: &emailverifier.ErrorMessage{Message: reply.ErrorMessage},
+			Error:        reply.ErrorMessage,
+		}
+	} else {
+		response := reply.Response
+		if response == nil {
+			response = Deliverable(emailAddress, query)
+		} else if response.EmailAddress == "" {
+			copied := *response
+			copied.EmailAddress = emailAddress
+			response = &copied
+		}
+		v = apiResponse{EvapiResponse: response}
+	}
+
+	var body []byte
+	var err error
+	if xmlFormat {
+		body, err = xml.Marshal(v)
+	} else if r, ok := v.(apiResponse); ok {
+		body, err = json.Marshal(r.EvapiResponse)
+	} else {
+		body, err = json.Marshal(v)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	return body
+}
+
+// Deliverable returns the response for the deliverable address with the checks requested by the query
+// parameters: DNS, SMTP, catch-all, free and disposable checks are set unless disabled with 0
+func Deliverable(emailAddress string, query url.Values) *emailverifier.EvapiResponse {
+	yes, no := emailverifier.StringBool(true), emailverifier.StringBool(false)
+
+	response := &emailverifier.EvapiResponse{
+		EmailAddress: emailAddress,
+		FormatCheck:  &yes,
+	}
+
+	if i := strings.LastIndexByte(emailAddress, '@'); i >= 0 {
+		response.Username, response.Domain = emailAddress[:i], strings.ToLower(emailAddress[i+1:])
+		response.MxRecords = []string{"mx." + response.Domain + "."}
+	}
+
+	enabled := func(name string) bool {
+		return query.Get(name) != "0"
+	}
+
+	if enabled("validateDNS") {
+		response.DnsCheck = &yes
+	} else {
+		response.MxRecords = nil
+	}
+	if enabled("validateSMTP") {
+		response.SmtpCheck = &yes
+	}
+	if enabled("checkCatchAll") {
+		response.CatchAllCheck = &no
+	}
+	if enabled("checkFree") {
+		response.FreeCheck = &no
+	}
+	if enabled("checkDisposable") {
+		response.DisposableCheck = &no
+	}
+
+	return response
+}
+
+// addressKey returns the address with the lower-cased domain
+func addressKey(emailAddress string) string {
+	if i := strings.LastIndexByte(emailAddress, '@'); i >= 0 {
+		return emailAddress[:i] + strings.ToLower(emailAddress[i:])
+	}
+	return emailAddress
+}
+
+// cloneValues returns the copy of the values
+func cloneValues(v url.Values) url.Values {
+	c := make(url.Values, len(v))
+	for k, vv := range v {
+		c[k] = append([]string(nil), vv...)
+	}
+	return c
+}
@@ -0,0 +1,140 @@
+package evapitest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	emailverifier "github.com/whois-api-llc/go-email-verifier"
+)
+
+// newClient creates the client for the server
+func newClient(server *Server, params emailverifier.ClientParams) *emailverifier.Client {
+	params.HTTPClient = server.Client()
+	params.EvapiBaseURL = server.URL()
+	return emailverifier.NewClient("at_test", params)
+}
+
+// TestServer tests the replies of the server
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	yes := emailverifier.StringBool(true)
+
+	server.HandleDomain("Mailinator.com", Respond(&emailverifier.EvapiResponse{DisposableCheck: &yes}))
+	server.HandleAddress("down@example.com", Error(http.StatusServiceUnavailable, "maintenance"))
+	server.HandleAddress("truncated@example.com", Reply{Truncate: 10})
+	server.HandleAddress("raw@example.com", Reply{Body: []byte(`{"emailAddress":"raw@example.com","smtpCheck":"false"}`)})
+	server.HandleAddress("error@example.com", Reply{ErrorMessage: "test error message"})
+
+	client := newClient(server, emailverifier.ClientParams{})
+	ctx := context.Background()
+
+	evapiResp, _, err := client.Get(ctx, "john@example.com", emailverifier.OptionCheckFree(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evapiResp.SmtpCheck == nil || !bool(*evapiResp.SmtpCheck) || evapiResp.FreeCheck != nil ||
+		evapiResp.Domain != "example.com" {
+		t.Errorf("Get() = %+v, expected the default deliverable response", evapiResp)
+	}
+	if q := server.LastQuery(); q.Get("checkFree") != "0" || q.Get("apiKey") != "" {
+		t.Errorf("LastQuery() = %v", q)
+	}
+
+	evapiResp, _, err = client.Get(ctx, "john@MAILINATOR.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evapiResp.DisposableCheck == nil || !bool(*evapiResp.DisposableCheck) ||
+		evapiResp.EmailAddress != "john@mailinator.com" {
+		t.Errorf("Get() = %+v, expected the domain response", evapiResp)
+	}
+
+	_, _, err = client.Get(ctx, "down@example.com")
+	var errResp emailverifier.ErrorResponse
+	if !errors.Is(err, emailverifier.ErrServerError) || !errors.As(err, &errResp) || errResp.Message != "maintenance" {
+		t.Errorf("Get() error = %v, expected server error", err)
+	}
+
+	if _, _, err = client.Get(ctx, "truncated@example.com"); err == nil {
+		t.Errorf("Get() expected error for the truncated body")
+	}
+
+	evapiResp, _, err = client.Get(ctx, "raw@example.com")
+	if err != nil || evapiResp.SmtpCheck == nil || bool(*evapiResp.SmtpCheck) {
+		t.Errorf("Get() = %+v, %v, expected the raw body", evapiResp, err)
+	}
+
+	_, _, err = client.Get(ctx, "error@example.com")
+	var errMessage emailverifier.ErrorMessage
+	if !errors.As(err, &errMessage) || errMessage.Message != "test error message" {
+		t.Errorf("Get() error = %v, expected the error message", err)
+	}
+
+	evapiResp, _, err = client.Get(ctx, "xml@example.com", emailverifier.OptionOutputFormat("XML"))
+	if err != nil || evapiResp.DnsCheck == nil || len(evapiResp.MxRecords) != 1 {
+		t.Errorf("Get() = %+v, %v, expected the XML response", evapiResp, err)
+	}
+
+	if got := server.Requests(); got != 7 {
+		t.Errorf("Requests() = %d, want 7", got)
+	}
+
+	server.Reset()
+	if got := len(server.Queries()); got != 0 {
+		t.Errorf("Queries() after Reset() = %d, want 0", got)
+	}
+}
+
+// TestServerScript tests the replies used several times, the latency, the API key and the rate limit
+func TestServerScript(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.HandleDefault(Reply{StatusCode: http.StatusBadGateway, Times: 2})
+	server.HandleDefault(Reply{Latency: 50 * time.Millisecond})
+	server.RequireAPIKey("at_test")
+
+	policy := emailverifier.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	client := newClient(server, emailverifier.ClientParams{RetryPolicy: policy})
+
+	ctx := context.Background()
+
+	_, resp, err := client.Get(ctx, "john@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", resp.Attempts)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, _, err = client.Get(timeoutCtx, "john@example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	other := newClient(server, emailverifier.ClientParams{})
+	if _, _, err = emailverifier.NewClient("wrong", emailverifier.ClientParams{
+		HTTPClient:   server.Client(),
+		EvapiBaseURL: server.URL(),
+	}).Get(ctx, "john@example.com"); !errors.Is(err, emailverifier.ErrAuthentication) {
+		t.Errorf("Get() error = %v, want %v", err, emailverifier.ErrAuthentication)
+	}
+
+	server.RateLimit(1, time.Minute)
+	if _, _, err = other.Get(ctx, "john@example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = other.Get(ctx, "jane@example.com")
+	var errResp emailverifier.ErrorResponse
+	if !errors.Is(err, emailverifier.ErrRateLimited) || !errors.As(err, &errResp) || errResp.RetryAfter <= 0 {
+		t.Errorf("Get() error = %v, expected rate limit with Retry-After", err)
+	}
+}
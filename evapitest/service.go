@@ -0,0 +1,370 @@
+package evapitest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	emailverifier "github.com/whois-api-llc/go-email-verifier"
+)
+
+// Call is the recorded call of Service
+type Call struct {
+	// Method is the called method: Get, GetRaw or GetMany
+	Method string
+
+	// EmailAddress is the email address as it was passed
+	EmailAddress string
+
+	// Query is the query the client would send, including outputFormat, without the API key
+	Query url.Values
+}
+
+// Service is the in-memory EvapiService implementation which needs no HTTP.
+// It answers every address with a deliverable response by default. Replies are scripted
+// the same way as for Server, except that Truncate is ignored and Err is returned as is:
+//
+//	service := evapitest.NewService()
+//	service.HandleAddress("down@example.com", evapitest.Error(http.StatusServiceUnavailable, "maintenance"))
+//
+//	client := emailverifier.NewClient("at_test", emailverifier.ClientParams{})
+//	client.EvapiService = service
+type Service struct {
+	mu        sync.Mutex
+	addresses map[string][]*Reply
+	domains   map[string][]*Reply
+	fallback  []*Reply
+	calls     []Call
+}
+
+var _ emailverifier.EvapiService = &Service{}
+
+// NewService creates Service
+func NewService() *Service {
+	return &Service{
+		addresses: make(map[string][]*Reply),
+		domains:   make(map[string][]*Reply),
+	}
+}
+
+// HandleAddress adds the reply for the email address. Replies for the same address are used in order
+func (s *Service) HandleAddress(emailAddress string, reply Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addresses[addressKey(emailAddress)] = append(s.addresses[addressKey(emailAddress)], &reply)
+}
+
+// HandleDomain adds the reply for the email addresses of the domain which have no own replies
+func (s *Service) HandleDomain(domain string, reply Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	domain = strings.ToLower(domain)
+	s.domains[domain] = append(s.domains[domain], &reply)
+}
+
+// HandleDefault adds the reply for the email addresses which have no address or domain replies
+func (s *Service) HandleDefault(reply Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fallback = append(s.fallback, &reply)
+}
+
+// Calls returns the recorded calls
+func (s *Service) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calls := make([]Call, len(s.calls))
+	for i, call := range s.calls {
+		call.Query = cloneValues(call.Query)
+		calls[i] = call
+	}
+	return calls
+}
+
+// CallCount returns the number of calls for the email address
+func (s *Service) CallCount(emailAddress string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, call := range s.calls {
+		if addressKey(call.EmailAddress) == addressKey(emailAddress) {
+			count++
+		}
+	}
+	return count
+}
+
+// LastCall returns the last recorded call. It's the zero value if there were no calls
+func (s *Service) LastCall() Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.calls) == 0 {
+		return Call{}
+	}
+
+	call := s.calls[len(s.calls)-1]
+	call.Query = cloneValues(call.Query)
+	return call
+}
+
+// AssertCalled fails the test if the email address was not verified
+func (s *Service) AssertCalled(t testing.TB, emailAddress string) {
+	t.Helper()
+
+	if s.CallCount(emailAddress) == 0 {
+		t.Errorf("%s was not verified", emailAddress)
+	}
+}
+
+// AssertNotCalled fails the test if the email address was verified
+func (s *Service) AssertNotCalled(t testing.TB, emailAddress string) {
+	t.Helper()
+
+	if count := s.CallCount(emailAddress); count > 0 {
+		t.Errorf("%s was verified %d times, expected none", emailAddress, count)
+	}
+}
+
+// AssertCallCount fails the test if the number of calls differs
+func (s *Service) AssertCallCount(t testing.TB, want int) {
+	t.Helper()
+
+	if got := len(s.Calls()); got != want {
+		t.Errorf("got %d calls, expected %d", got, want)
+	}
+}
+
+// Reset removes the replies and the recorded calls
+func (s *Service) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addresses = make(map[string][]*Reply)
+	s.domains = make(map[string][]*Reply)
+	s.fallback = nil
+	s.calls = nil
+}
+
+// Get returns the scripted response
+func (s *Service) Get(
+	ctx context.Context,
+	emailAddress string,
+	opts ...emailverifier.Option,
+) (*emailverifier.EvapiResponse, *emailverifier.Response, error) {
+
+	return s.get(ctx, "Get", emailAddress, opts)
+}
+
+// GetRaw returns the scripted response as Response with the body in the requested format
+func (s *Service) GetRaw(
+	ctx context.Context,
+	emailAddress string,
+	opts ...emailverifier.Option,
+) (*emailverifier.Response, error) {
+
+	reply, query, err := s.call(ctx, "GetRaw", emailAddress, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := response(reply, emailAddress, query)
+
+	if err = checkStatus(reply, resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// GetMany returns the scripted responses in the input order
+func (s *Service) GetMany(
+	ctx context.Context,
+	emailAddresses []string,
+	opts ...emailverifier.Option,
+) []emailverifier.BatchResult {
+
+	results := make([]emailverifier.BatchResult, len(emailAddresses))
+
+	for i, emailAddress := range emailAddresses {
+		results[i].EmailAddress = emailAddress
+		results[i].EvapiResponse, results[i].Response, results[i].Err = s.get(ctx, "GetMany", emailAddress, opts)
+	}
+
+	return results
+}
+
+// get returns the parsed scripted response
+func (s *Service) get(
+	ctx context.Context,
+	method string,
+	emailAddress string,
+	opts []emailverifier.Option,
+) (*emailverifier.EvapiResponse, *emailverifier.Response, error) {
+
+	reply, query, err := s.call(ctx, method, emailAddress, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := response(reply, emailAddress, query)
+
+	if err = checkStatus(reply, resp); err != nil {
+		return nil, resp, err
+	}
+
+	if reply.ErrorMessage != "" {
+		return nil, resp, emailverifier.ErrorMessage{Message: reply.ErrorMessage}
+	}
+
+	if reply.Body != nil {
+		var evapiResp emailverifier.EvapiResponse
+		if err = json.Unmarshal(reply.Body, &evapiResp); err != nil {
+			return nil, resp, fmt.Errorf("cannot parse response: %w", err)
+		}
+		return &evapiResp, resp, nil
+	}
+
+	return replyResponse(reply, emailAddress, query), resp, nil
+}
+
+// call records the call and returns the reply with the query
+func (s *Service) call(
+	ctx context.Context,
+	method string,
+	emailAddress string,
+	opts []emailverifier.Option,
+) (*Reply, url.Values, error) {
+
+	if emailAddress == "" {
+		return nil, nil, &emailverifier.ArgError{Name: "emailAddress", Message: "cannot be empty"}
+	}
+
+	query := url.Values{}
+	query.Set("outputFormat", "JSON")
+	for _, opt := range opts {
+		opt(query)
+	}
+	query.Set("emailAddress", emailAddress)
+
+	reply := s.record(method, emailAddress, query)
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if reply.Latency > 0 {
+		timer := time.NewTimer(reply.Latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, ctx.Err()
+		}
+	}
+
+	if reply.Err != nil {
+		return nil, nil, reply.Err
+	}
+
+	return reply, query, nil
+}
+
+// record saves the call and returns the reply for it
+func (s *Service) record(method, emailAddress string, query url.Values) *Reply {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, Call{Method: method, EmailAddress: emailAddress, Query: query})
+
+	if reply := next(s.addresses, addressKey(emailAddress)); reply != nil {
+		return reply
+	}
+
+	if i := strings.LastIndexByte(emailAddress, '@'); i >= 0 {
+		if reply := next(s.domains, strings.ToLower(emailAddress[i+1:])); reply != nil {
+			return reply
+		}
+	}
+
+	if reply := nextReply(&s.fallback); reply != nil {
+		return reply
+	}
+
+	return &Reply{}
+}
+
+// response returns Response with the body of the reply in the requested format
+func response(reply *Reply, emailAddress string, query url.Values) *emailverifier.Response {
+	statusCode := reply.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	body := reply.Body
+	if body == nil {
+		body = encode(reply, emailAddress, query, strings.EqualFold(query.Get("outputFormat"), "XML"))
+	}
+
+	resp := emailverifier.NewResponse(statusCode, body)
+	for k, v := range reply.Header {
+		resp.Header[k] = append([]string(nil), v...)
+	}
+
+	return resp
+}
+
+// checkStatus returns ErrorResponse as the client does if the status code of the response is not 2xx
+func checkStatus(reply *Reply, resp *emailverifier.Response) error {
+	c := resp.StatusCode
+	if c >= 200 && c <= 299 {
+		return nil
+	}
+
+	errorResponse := emailverifier.ErrorResponse{Response: resp.Response, StatusCode: c}
+	if reply.ErrorMessage != "" {
+		errorResponse.Message = reply.ErrorMessage
+		errorResponse.ErrorMessage = &emailverifier.ErrorMessage{Message: reply.ErrorMessage}
+	}
+	errorResponse.RetryAfter = retryAfter(resp.Header.Get("Retry-After"))
+
+	return errorResponse
+}
+
+// retryAfter returns the delay of the Retry-After header given either in seconds or as an HTTP date
+func retryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && time.Until(date) > 0 {
+		return time.Until(date)
+	}
+
+	return 0
+}
+
+// replyResponse returns the response of the reply for the email address
+func replyResponse(reply *Reply, emailAddress string, query url.Values) *emailverifier.EvapiResponse {
+	if reply.Response == nil {
+		return Deliverable(emailAddress, query)
+	}
+
+	copied := *reply.Response
+	if copied.EmailAddress == "" {
+		copied.EmailAddress = emailAddress
+	}
+	return &copied
+}
@@ -0,0 +1,124 @@
+package evapitest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	emailverifier "github.com/whois-api-llc/go-email-verifier"
+)
+
+// TestService tests the scripted responses and the recorded calls of the in-memory service
+func TestService(t *testing.T) {
+	yes := emailverifier.StringBool(true)
+
+	service := NewService()
+	service.HandleDomain("mailinator.com", Respond(&emailverifier.EvapiResponse{DisposableCheck: &yes}))
+	service.HandleAddress("down@example.com", Reply{StatusCode: http.StatusBadGateway, Times: 1})
+	service.HandleAddress("broken@example.com", Reply{Err: errors.New("connection reset")})
+
+	client := emailverifier.NewClient("at_test", emailverifier.ClientParams{})
+	client.EvapiService = service
+
+	ctx := context.Background()
+
+	resp, raw, err := client.Get(ctx, "john@example.com", emailverifier.OptionCheckFree(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.EmailAddress != "john@example.com" || resp.SmtpCheck == nil || resp.FreeCheck != nil {
+		t.Errorf("Get() = %+v, expected deliverable response without the free check", resp)
+	}
+	if raw.StatusCode != http.StatusOK || len(raw.Body) == 0 {
+		t.Errorf("Get() response = %d %s", raw.StatusCode, raw.Body)
+	}
+
+	resp, _, err = client.Get(ctx, "jane@Mailinator.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.DisposableCheck == nil || !bool(*resp.DisposableCheck) || resp.EmailAddress != "jane@Mailinator.com" {
+		t.Errorf("Get() = %+v, expected disposable response", resp)
+	}
+
+	_, _, err = client.Get(ctx, "down@example.com")
+	if !errors.Is(err, emailverifier.ErrServerError) {
+		t.Errorf("Get() error = %v, want %v", err, emailverifier.ErrServerError)
+	}
+	if _, _, err = client.Get(ctx, "down@example.com"); err != nil {
+		t.Errorf("Get() error = %v, expected the reply to be used up", err)
+	}
+
+	if _, _, err = client.Get(ctx, "broken@example.com"); err == nil || err.Error() != "connection reset" {
+		t.Errorf("Get() error = %v, want connection reset", err)
+	}
+
+	raw, err = client.GetRaw(ctx, "john@example.com", emailverifier.OptionOutputFormat("XML"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw.Body[:13]) != "<ApiResponse>" {
+		t.Errorf("GetRaw() body = %s, expected XML", raw.Body)
+	}
+
+	service.HandleAddress("limited@example.com", Reply{
+		StatusCode:   http.StatusTooManyRequests,
+		ErrorMessage: "Too many requests",
+		Header:       http.Header{"Retry-After": {"2"}},
+		Times:        1,
+	})
+	raw, err = client.GetRaw(ctx, "limited@example.com")
+	var errResponse emailverifier.ErrorResponse
+	if !errors.As(err, &errResponse) || !errors.Is(err, emailverifier.ErrRateLimited) {
+		t.Fatalf("GetRaw() error = %v, want rate limited ErrorResponse", err)
+	}
+	if errResponse.RetryAfter != 2*time.Second || errResponse.Message != "Too many requests" {
+		t.Errorf("GetRaw() error = %+v, expected Retry-After 2s", errResponse)
+	}
+	if raw == nil || raw.StatusCode != http.StatusTooManyRequests || raw.Header.Get("Retry-After") != "2" {
+		t.Errorf("GetRaw() response = %+v, expected 429 with Retry-After", raw)
+	}
+
+	results := client.GetMany(ctx, []string{"a@example.com", "down@example.com"})
+	if len(results) != 2 || results[0].Err != nil || results[1].Err != nil {
+		t.Errorf("GetMany() = %+v", results)
+	}
+
+	if _, _, err = client.Get(ctx, ""); err == nil {
+		t.Error("Get() expected error for empty address")
+	}
+
+	service.AssertCalled(t, "john@EXAMPLE.com")
+	service.AssertNotCalled(t, "nobody@example.com")
+	service.AssertCallCount(t, 9)
+
+	if count := service.CallCount("down@example.com"); count != 3 {
+		t.Errorf("CallCount() = %d, want 3", count)
+	}
+
+	call := service.LastCall()
+	if call.Method != "GetMany" || call.EmailAddress != "down@example.com" || call.Query.Get("outputFormat") != "JSON" {
+		t.Errorf("LastCall() = %+v", call)
+	}
+	if query := service.Calls()[0].Query; query.Get("checkFree") != "0" {
+		t.Errorf("Calls()[0].Query = %v, expected checkFree=0", query)
+	}
+
+	service.Reset()
+	service.AssertCallCount(t, 0)
+}
+
+// TestServiceLatency tests the context cancellation while the reply is delayed
+func TestServiceLatency(t *testing.T) {
+	service := NewService()
+	service.HandleDefault(Reply{Latency: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := service.Get(ctx, "john@example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
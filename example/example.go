@@ -14,14 +14,14 @@ func GetData(apikey string) {
 
 	// Get parsed Email Verification API response as a model instance
 	evapiResp, resp, err := client.EvapiService.Get(context.Background(), "support@whoisxmlapi.com",
-		// this option is ignored, as the inner parser works with JSON only
+		// the response is requested and parsed in XML format
 		emailverifier.OptionOutputFormat("XML"),
 		// this option results in the catchAll check being omitted
 		emailverifier.OptionCheckCatchAll(0))
 
 	if err != nil {
 		// Handle error message returned by server
-		var apiErr *emailverifier.ErrorMessage
+		var apiErr emailverifier.ErrorMessage
 		if errors.As(err, &apiErr) {
 			log.Println(apiErr.Message)
 		}
@@ -42,7 +42,7 @@ func GetData(apikey string) {
 			strconv.FormatBool(bool(*evapiResp.SmtpCheck)))
 	}
 
-	log.Println("raw response is always in JSON format. Most likely you don't need it.")
+	log.Println("raw response is in the requested format. Most likely you don't need it.")
 	log.Printf("raw response: %s\n", string(resp.Body))
 }
 
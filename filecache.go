@@ -0,0 +1,378 @@
+package emailverifier
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// compactSlack is the number of stale records in the FileCache log which triggers compaction
+const compactSlack = 1000
+
+// FileCache is the persistent Cache backed by an append-only log file.
+// Every Set appends a checksummed record, so a crash may lose only the record being written:
+// an incomplete or corrupted record is skipped when the file is opened. The log is compacted
+// into a new file atomically renamed over the old one when stale records accumulate.
+// It evicts least recently used entries and entries older than TTL like LRUCache,
+// but the recency of Get calls is not persisted. The file must not be shared between processes
+type FileCache struct {
+	mu sync.Mutex
+
+	name string
+	file *os.File
+
+	size int
+	ttl  time.Duration
+
+	entries *list.List
+	index   map[string]*list.Element
+
+	// records is the number of records in the log
+	records int
+
+	// err is the last write error
+	err error
+
+	now func() time.Time
+}
+
+// fileRecord is the FileCache log record. Body is encoded in base64, so it's stored byte for byte
+type fileRecord struct {
+	Key       string    `json:"key"`
+	Body      []byte    `json:"body"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Audit     Audit     `json:"audit"`
+}
+
+var _ Cache = &FileCache{}
+
+// OpenFileCache opens or creates FileCache in the file holding up to size entries for ttl.
+// Zero ttl means entries don't expire. It must be closed with Close
+func OpenFileCache(name string, size int, ttl time.Duration) (*FileCache, error) {
+	if size < 1 {
+		size = 1
+	}
+
+	c := &FileCache{
+		name:    name,
+		size:    size,
+		ttl:     ttl,
+		entries: list.New(),
+		index:   make(map[string]*list.Element),
+		now:     time.Now,
+	}
+
+	if err := c.open(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// open loads the log and opens it for appending
+func (c *FileCache) open() error {
+	file, err := os.OpenFile(c.name, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open cache: %w", err)
+	}
+
+	valid, err := c.load(file)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("cannot read cache: %w", err)
+	}
+
+	// The incomplete record written during a crash is cut off, so the next record starts on a new line
+	if err = file.Truncate(valid); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("cannot open cache: %w", err)
+	}
+	if _, err = file.Seek(valid, io.SeekStart); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("cannot open cache: %w", err)
+	}
+
+	c.file = file
+
+	return nil
+}
+
+// load reads the records from the log and returns the size of its complete lines
+func (c *FileCache) load(r io.Reader) (int64, error) {
+	reader := bufio.NewReader(r)
+
+	var valid int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return valid, nil
+		}
+		if err != nil {
+			return valid, err
+		}
+
+		valid += int64(len(line))
+		c.records++
+
+		record, ok := decodeRecord(line)
+		if !ok {
+			continue
+		}
+
+		c.add(record.Key, &CacheEntry{Body: record.Body, FetchedAt: record.FetchedAt, Audit: record.Audit})
+	}
+}
+
+// encodeRecord returns the log line: the CRC-32 checksum of the record and the record in JSON
+func encodeRecord(key string, entry *CacheEntry) ([]byte, error) {
+	data, err := json.Marshal(fileRecord{
+		Key:       key,
+		Body:      entry.Body,
+		FetchedAt: entry.FetchedAt,
+		Audit:     entry.Audit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode cache entry: %w", err)
+	}
+
+	line := make([]byte, 0, len(data)+10)
+	line = append(line, fmt.Sprintf("%08x ", crc32.ChecksumIEEE(data))...)
+	line = append(line, data...)
+	line = append(line, '\n')
+
+	return line, nil
+}
+
+// decodeRecord parses the log line and reports whether it's intact
+func decodeRecord(line []byte) (*fileRecord, bool) {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	if len(line) < 9 || line[8] != ' ' {
+		return nil, false
+	}
+
+	checksum, err := strconv.ParseUint(string(line[:8]), 16, 32)
+	if err != nil || uint32(checksum) != crc32.ChecksumIEEE(line[9:]) {
+		return nil, false
+	}
+
+	var record fileRecord
+	if err = json.Unmarshal(line[9:], &record); err != nil {
+		return nil, false
+	}
+
+	return &record, true
+}
+
+// Get returns the entry stored with the key if it's not expired
+func (c *FileCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.index[key]
+	if !ok {
+		return nil, false
+	}
+
+	item := el.Value.(*lruItem)
+	if c.expired(item.entry) {
+		c.entries.Remove(el)
+		delete(c.index, key)
+		return nil, false
+	}
+
+	c.entries.MoveToFront(el)
+
+	return item.entry, true
+}
+
+// Set stores the entry with the key evicting the least recently used entry if the cache is full
+// Write errors are reported by Err
+func (c *FileCache) Set(key string, entry *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		c.err = os.ErrClosed
+		return
+	}
+
+	line, err := encodeRecord(key, entry)
+	if err != nil {
+		c.err = err
+		return
+	}
+
+	if _, err = c.file.Write(line); err != nil {
+		c.err = fmt.Errorf("cannot write cache: %w", err)
+		return
+	}
+	c.records++
+
+	c.add(key, entry)
+
+	if c.records > 2*c.entries.Len()+compactSlack {
+		if err = c.compact(); err != nil {
+			c.err = err
+		}
+	}
+}
+
+// add stores the entry in memory evicting the least recently used entry if the cache is full
+func (c *FileCache) add(key string, entry *CacheEntry) {
+	if c.expired(entry) {
+		if el, ok := c.index[key]; ok {
+			c.entries.Remove(el)
+			delete(c.index, key)
+		}
+		return
+	}
+
+	if el, ok := c.index[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		c.entries.MoveToFront(el)
+		return
+	}
+
+	c.index[key] = c.entries.PushFront(&lruItem{key: key, entry: entry})
+
+	for c.entries.Len() > c.size {
+		el := c.entries.Back()
+		c.entries.Remove(el)
+		delete(c.index, el.Value.(*lruItem).key)
+	}
+}
+
+// expired reports whether the entry is older than TTL
+func (c *FileCache) expired(entry *CacheEntry) bool {
+	return c.ttl > 0 && c.now().Sub(entry.FetchedAt) > c.ttl
+}
+
+// Compact rewrites the log with the current entries only
+func (c *FileCache) Compact() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return os.ErrClosed
+	}
+
+	return c.compact()
+}
+
+// compact writes the entries to the temporary file and renames it over the log
+func (c *FileCache) compact() error {
+	tmp := c.name + ".tmp"
+
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot compact cache: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	records := 0
+
+	// The least recently used entries go first to be evicted first when the log is loaded
+	for el := c.entries.Back(); el != nil; {
+		item := el.Value.(*lruItem)
+		prev := el.Prev()
+
+		if c.expired(item.entry) {
+			c.entries.Remove(el)
+			delete(c.index, item.key)
+			el = prev
+			continue
+		}
+
+		line, err := encodeRecord(item.key, item.entry)
+		if err == nil {
+			_, err = writer.Write(line)
+		}
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(tmp)
+			return fmt.Errorf("cannot compact cache: %w", err)
+		}
+		records++
+		el = prev
+	}
+
+	err = writer.Flush()
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, c.name)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("cannot compact cache: %w", err)
+	}
+
+	_ = c.file.Close()
+
+	c.file, err = os.OpenFile(c.name, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open cache: %w", err)
+	}
+	c.records = records
+
+	return nil
+}
+
+// Len returns the number of entries in the cache including expired ones
+func (c *FileCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries.Len()
+}
+
+// Err returns the last error occurred while writing the cache
+func (c *FileCache) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// Sync commits the written entries to stable storage
+func (c *FileCache) Sync() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return os.ErrClosed
+	}
+
+	return c.file.Sync()
+}
+
+// Close syncs and closes the file. The cache can't be used after that
+func (c *FileCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return os.ErrClosed
+	}
+
+	err := c.file.Sync()
+	if closeErr := c.file.Close(); err == nil {
+		err = closeErr
+	}
+	c.file = nil
+
+	return err
+}
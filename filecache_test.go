@@ -0,0 +1,168 @@
+package emailverifier
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestFileCache tests persistence and eviction of the FileCache entries
+func TestFileCache(t *testing.T) {
+
+	name := filepath.Join(t.TempDir(), "cache.log")
+	now := time.Now()
+
+	created, _ := parseTime("2022-04-01 10:00:00 UTC")
+	audit := Audit{AuditCreatedDate: created, AuditUpdatedDate: created}
+
+	c, err := OpenFileCache(name, 2, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Set("0", &CacheEntry{Body: []byte(`{"emailAddress":"0"}`), FetchedAt: now, Audit: audit})
+	c.Set("1", &CacheEntry{Body: []byte("1\xff\xfe"), FetchedAt: now})
+	if _, ok := c.Get("0"); !ok {
+		t.Errorf("Get() expected entry 0")
+	}
+	c.Set("2", &CacheEntry{Body: []byte("2"), FetchedAt: now.Add(-2 * time.Hour)})
+
+	if _, ok := c.Get("2"); ok {
+		t.Errorf("Get() expected expired entry 2 to be omitted")
+	}
+	if err = c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Err() != nil {
+		t.Errorf("Err() = %v", c.Err())
+	}
+
+	c, err = OpenFileCache(name, 2, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	entry, ok := c.Get("0")
+	if !ok || string(entry.Body) != `{"emailAddress":"0"}` || !entry.FetchedAt.Equal(now) || entry.Audit != audit {
+		t.Errorf("Get() = %+v, %v, expected entry 0 with the dates", entry, ok)
+	}
+	if entry, ok = c.Get("1"); !ok || string(entry.Body) != "1\xff\xfe" {
+		t.Errorf("Get() = %+v, %v, expected entry 1 with the invalid UTF-8 body intact", entry, ok)
+	}
+
+	c.Set("3", &CacheEntry{Body: []byte("3"), FetchedAt: now})
+
+	if _, ok = c.Get("0"); ok {
+		t.Errorf("Get() expected least recently used entry 0 to be evicted")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+
+	if err = c.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines != 2 {
+		t.Errorf("compacted log has %d records, want 2", lines)
+	}
+
+	c.Set("4", &CacheEntry{Body: []byte("4"), FetchedAt: now})
+	for _, key := range []string{"3", "4"} {
+		if _, ok = c.Get(key); !ok {
+			t.Errorf("Get() expected entry %s after compaction", key)
+		}
+	}
+}
+
+// TestFileCacheRecovery tests that the corrupted and incomplete records are skipped
+func TestFileCacheRecovery(t *testing.T) {
+
+	name := filepath.Join(t.TempDir(), "cache.log")
+	now := time.Now()
+
+	var log []byte
+	for _, key := range []string{"a", "b", "c"} {
+		line, err := encodeRecord(key, &CacheEntry{Body: []byte(key), FetchedAt: now})
+		if err != nil {
+			t.Fatal(err)
+		}
+		log = append(log, line...)
+	}
+
+	// The record b is corrupted and the record d is cut off by a crash
+	log = bytes.Replace(log, []byte(`"key":"b"`), []byte(`"key":"x"`), 1)
+	line, _ := encodeRecord("d", &CacheEntry{Body: []byte("d"), FetchedAt: now})
+	log = append(log, line[:len(line)/2]...)
+
+	if err := os.WriteFile(name, log, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := OpenFileCache(name, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true, "d": false} {
+		if _, ok := c.Get(key); ok != want {
+			t.Errorf("Get(%q) ok = %v, want %v", key, ok, want)
+		}
+	}
+
+	c.Set("e", &CacheEntry{Body: []byte("e"), FetchedAt: now})
+	if err = c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = OpenFileCache(name, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if entry, ok := c.Get("e"); !ok || string(entry.Body) != "e" {
+		t.Errorf("Get() = %v, %v, expected entry e written after recovery", entry, ok)
+	}
+}
+
+// TestFileCacheConcurrency tests concurrent use of FileCache with compaction
+func TestFileCacheConcurrency(t *testing.T) {
+
+	c, err := OpenFileCache(filepath.Join(t.TempDir(), "cache.log"), 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := strconv.Itoa(i % 20)
+				c.Set(key, &CacheEntry{Body: []byte(key), FetchedAt: time.Now()})
+				if entry, ok := c.Get(key); ok && string(entry.Body) != key {
+					t.Errorf("Get(%q) = %s", key, entry.Body)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if c.Err() != nil {
+		t.Errorf("Err() = %v", c.Err())
+	}
+	if c.Len() != 10 {
+		t.Errorf("Len() = %d, want 10", c.Len())
+	}
+}
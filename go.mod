@@ -1,3 +1,9 @@
 module github.com/whois-api-llc/go-email-verifier
 
 go 1.17
+
+require (
+	golang.org/x/net v0.17.0
+	golang.org/x/text v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
+)
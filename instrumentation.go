@@ -0,0 +1,309 @@
+package emailverifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Call outcomes reported by CallInfo.Outcome
+const (
+	OutcomeSuccess     = "success"
+	OutcomeCacheHit    = "cache_hit"
+	OutcomeSynthesized = "synthesized"
+	OutcomeAPIError    = "api_error"
+	OutcomeError       = "error"
+)
+
+// Instrumentation receives events about Email Verification API calls, e.g. to record spans and metrics
+// Implementations must be safe for concurrent use
+type Instrumentation interface {
+	// CallStarted is called before the call. The returned context is used for the call, so it may carry a span
+	CallStarted(ctx context.Context, call *CallInfo) context.Context
+
+	// CallFinished is called after the call with the context returned by CallStarted
+	CallFinished(ctx context.Context, call *CallInfo)
+}
+
+// CallInfo describes the Email Verification API call
+// It never contains the API key or the email address, only its domain and hash
+type CallInfo struct {
+	// Operation is the called EvapiService method name
+	Operation string
+
+	// Domain is the domain of the email address in ASCII form
+	Domain string
+
+	// AddressHash is the hex-encoded SHA-256 hash of the normalized email address
+	AddressHash string
+
+	// Options are the query parameters set by the options
+	Options map[string]string
+
+	// Start is the time the call started
+	Start time.Time
+
+	// Duration is the call duration. It's set when the call is finished
+	Duration time.Duration
+
+	// StatusCode is the response status code. It's zero if there is no response
+	StatusCode int
+
+	// Attempts is the number of the requests sent
+	Attempts int
+
+	// CacheHit indicates that the response is taken from the cache
+	CacheHit bool
+
+	// Synthesized indicates that the response is produced locally without an API request
+	Synthesized bool
+
+	// Err is the error returned by the call with the email address removed from the message
+	// errors.Is still matches the original error, e.g. context.Canceled or ErrRateLimited
+	Err error
+}
+
+// emailAddressPattern matches the emailAddress query parameter value
+var emailAddressPattern = regexp.MustCompile(`(?i)(emailAddress=)[^&#\s"]*`)
+
+// callError is the call error with the email address removed from the message
+type callError struct {
+	msg string
+	err error
+
+	// api indicates that the API responded with an error
+	api bool
+}
+
+// Error returns the error message without the email address
+func (e *callError) Error() string {
+	return e.msg
+}
+
+// Is reports whether the original error matches the target
+func (e *callError) Is(target error) bool {
+	return errors.Is(e.err, target)
+}
+
+// sanitizeError returns the error with the email address removed. The original error isn't exposed
+// by Unwrap because it may carry the address, e.g. in the request URL
+func sanitizeError(err error, emailAddress, address string) error {
+	if err == nil {
+		return nil
+	}
+
+	var errResponse ErrorResponse
+	var errMessage ErrorMessage
+
+	msg := emailAddressPattern.ReplaceAllString(err.Error(), "${1}"+redacted)
+	for _, s := range []string{emailAddress, address, url.QueryEscape(emailAddress), url.QueryEscape(address)} {
+		if s != "" {
+			msg = strings.ReplaceAll(msg, s, redacted)
+		}
+	}
+
+	return &callError{
+		msg: msg,
+		err: err,
+		api: errors.As(err, &errResponse) || errors.As(err, &errMessage),
+	}
+}
+
+// Outcome returns the call outcome: success | cache_hit | synthesized | api_error | error
+func (c *CallInfo) Outcome() string {
+	var errResponse ErrorResponse
+	var errMessage ErrorMessage
+	var errCall *callError
+
+	switch {
+	case c.Err != nil && errors.As(c.Err, &errCall):
+		if errCall.api {
+			return OutcomeAPIError
+		}
+		return OutcomeError
+	case c.Err != nil && (errors.As(c.Err, &errResponse) || errors.As(c.Err, &errMessage)):
+		return OutcomeAPIError
+	case c.Err != nil:
+		return OutcomeError
+	case c.CacheHit:
+		return OutcomeCacheHit
+	case c.Synthesized:
+		return OutcomeSynthesized
+	}
+	return OutcomeSuccess
+}
+
+// instrument notifies the instrumentation about the started call
+// and returns the context for the call and the function to be called when it's finished
+func (c *Client) instrument(
+	ctx context.Context,
+	operation string,
+	emailAddress string,
+	opts []Option,
+) (context.Context, func(resp *Response, err error)) {
+
+	if c.instrumentation == nil {
+		return ctx, func(*Response, error) {}
+	}
+
+	call := &CallInfo{
+		Operation: operation,
+		Options:   make(map[string]string),
+		Start:     time.Now(),
+	}
+
+	address := queryAddress(emailAddress, normalize(emailAddress))
+	if i := strings.LastIndexByte(address, '@'); i >= 0 {
+		call.Domain = strings.ToLower(address[i+1:])
+	}
+	if address != "" {
+		sum := sha256.Sum256([]byte(address))
+		call.AddressHash = hex.EncodeToString(sum[:])
+	}
+
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+	for k := range query {
+		call.Options[k] = query.Get(k)
+	}
+
+	ctx = c.instrumentation.CallStarted(ctx, call)
+
+	return ctx, func(resp *Response, err error) {
+		call.Duration = time.Since(call.Start)
+		call.Err = sanitizeError(err, emailAddress, address)
+
+		var errAttempts *AttemptsError
+		if resp != nil {
+			call.Attempts = resp.Attempts
+			call.CacheHit = resp.CacheHit
+			call.Synthesized = resp.Synthesized
+			if resp.Response != nil {
+				call.StatusCode = resp.StatusCode
+			}
+		} else if errors.As(err, &errAttempts) {
+			call.Attempts = errAttempts.Attempts
+		}
+		c.instrumentation.CallFinished(ctx, call)
+	}
+}
+
+// defaultLatencyBuckets are the upper bounds of the Metrics latency histogram buckets
+var defaultLatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Metrics is the Instrumentation counting calls by outcome and collecting the latency histogram
+type Metrics struct {
+	mu sync.Mutex
+
+	buckets  []time.Duration
+	counts   []uint64
+	outcomes map[string]uint64
+	calls    uint64
+	sum      time.Duration
+}
+
+var _ Instrumentation = &Metrics{}
+
+// LatencyBucket is the latency histogram bucket
+type LatencyBucket struct {
+	// UpperBound is the inclusive upper bound of the bucket. It's zero for the last unbounded bucket
+	UpperBound time.Duration
+
+	// Count is the number of calls which took longer than the previous bucket bound and at most UpperBound
+	Count uint64
+}
+
+// MetricsSnapshot is a snapshot of the collected metrics
+type MetricsSnapshot struct {
+	// Calls is the total number of calls
+	Calls uint64
+
+	// Outcomes is the number of calls by outcome
+	Outcomes map[string]uint64
+
+	// Latency is the latency histogram
+	Latency []LatencyBucket
+
+	// LatencySum is the total duration of all calls
+	LatencySum time.Duration
+}
+
+// NewMetrics creates Metrics with the latency histogram buckets bounds.
+// Default bounds: 50ms, 100ms, 250ms, 500ms, 1s, 2.5s, 5s, 10s.
+func NewMetrics(buckets ...time.Duration) *Metrics {
+	if len(buckets) == 0 {
+		buckets = defaultLatencyBuckets
+	}
+
+	bounds := append([]time.Duration(nil), buckets...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	return &Metrics{
+		buckets:  bounds,
+		counts:   make([]uint64, len(bounds)+1),
+		outcomes: make(map[string]uint64),
+	}
+}
+
+// CallStarted does nothing
+func (m *Metrics) CallStarted(ctx context.Context, call *CallInfo) context.Context {
+	return ctx
+}
+
+// CallFinished records the call outcome and duration
+func (m *Metrics) CallFinished(ctx context.Context, call *CallInfo) {
+	i := sort.Search(len(m.buckets), func(i int) bool { return call.Duration <= m.buckets[i] })
+	outcome := call.Outcome()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls++
+	m.outcomes[outcome]++
+	m.counts[i]++
+	m.sum += call.Duration
+}
+
+// Snapshot returns the collected metrics
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := MetricsSnapshot{
+		Calls:      m.calls,
+		Outcomes:   make(map[string]uint64, len(m.outcomes)),
+		Latency:    make([]LatencyBucket, len(m.counts)),
+		LatencySum: m.sum,
+	}
+
+	for k, v := range m.outcomes {
+		snapshot.Outcomes[k] = v
+	}
+
+	for i, count := range m.counts {
+		snapshot.Latency[i].Count = count
+		if i < len(m.buckets) {
+			snapshot.Latency[i].UpperBound = m.buckets[i]
+		}
+	}
+
+	return snapshot
+}
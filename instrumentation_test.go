@@ -0,0 +1,167 @@
+package emailverifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+// recorder is the Instrumentation recording calls for testing
+type recorder struct {
+	mu    sync.Mutex
+	calls []CallInfo
+	spans int
+}
+
+// CallStarted starts the fake span
+func (r *recorder) CallStarted(ctx context.Context, call *CallInfo) context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans++
+	return context.WithValue(ctx, ctxKey{}, r.spans)
+}
+
+// CallFinished records the call
+func (r *recorder) CallFinished(ctx context.Context, call *CallInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ctx.Value(ctxKey{}) != r.spans {
+		panic("unexpected context")
+	}
+	r.calls = append(r.calls, *call)
+}
+
+// TestInstrumentation tests the calls instrumentation
+func TestInstrumentation(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("emailAddress") == "error@whoisxmlapi.com" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"emailAddress":"support@whoisxmlapi.com"}`))
+	}))
+	defer server.Close()
+
+	rec := &recorder{}
+	metrics := NewMetrics(time.Nanosecond, time.Hour)
+
+	api := newAPI(server, "")
+	api.validateSyntax = true
+
+	ctx := context.Background()
+
+	for _, instrumentation := range []Instrumentation{rec, metrics} {
+		api.instrumentation = instrumentation
+		api.cache = NewLRUCache(10, time.Hour)
+
+		_, _, _ = api.Get(ctx, "Support@WhoisXMLAPI.com", OptionCheckFree(0))
+		_, _, _ = api.Get(ctx, "Support@whoisxmlapi.com", OptionCheckFree(0))
+		_, _, _ = api.Get(ctx, "foo@@bar.com")
+		_, _ = api.GetRaw(ctx, "error@whoisxmlapi.com")
+		_, _ = api.GetRaw(ctx, "")
+	}
+
+	sum := sha256.Sum256([]byte("Support@whoisxmlapi.com"))
+
+	want := []CallInfo{
+		{Operation: "Get", Domain: "whoisxmlapi.com", AddressHash: hex.EncodeToString(sum[:]),
+			Options: map[string]string{"checkFree": "0"}, StatusCode: 200, Attempts: 1},
+		{Operation: "Get", Domain: "whoisxmlapi.com", Options: map[string]string{"checkFree": "0"},
+			StatusCode: 200, CacheHit: true},
+		{Operation: "Get", Domain: "bar.com", Options: map[string]string{}, StatusCode: 200, Synthesized: true},
+		{Operation: "GetRaw", Domain: "whoisxmlapi.com", Options: map[string]string{}, StatusCode: 429, Attempts: 1},
+		{Operation: "GetRaw", Options: map[string]string{}},
+	}
+	wantOutcomes := []string{OutcomeSuccess, OutcomeCacheHit, OutcomeSynthesized, OutcomeAPIError, OutcomeError}
+
+	if len(rec.calls) != len(want) {
+		t.Fatalf("recorded %d calls, want %d", len(rec.calls), len(want))
+	}
+
+	for i, call := range rec.calls {
+		w := want[i]
+		if call.Operation != w.Operation || call.Domain != w.Domain || call.StatusCode != w.StatusCode ||
+			call.Attempts != w.Attempts || call.CacheHit != w.CacheHit || call.Synthesized != w.Synthesized ||
+			fmt.Sprint(call.Options) != fmt.Sprint(w.Options) {
+			t.Errorf("call %d = %+v, want %+v", i, call, w)
+		}
+		if w.AddressHash != "" && call.AddressHash != w.AddressHash {
+			t.Errorf("call %d hash = %s, want %s", i, call.AddressHash, w.AddressHash)
+		}
+		if call.Outcome() != wantOutcomes[i] {
+			t.Errorf("call %d outcome = %s, want %s", i, call.Outcome(), wantOutcomes[i])
+		}
+		if call.Start.IsZero() || call.Duration <= 0 {
+			t.Errorf("call %d timing = %v, %v, expected to be set", i, call.Start, call.Duration)
+		}
+
+		attributes := fmt.Sprintf("%+v", call)
+		for _, secret := range []string{apiKey, "upport@", "error@", "foo@"} {
+			if strings.Contains(attributes, secret) {
+				t.Errorf("call %d leaks %q: %s", i, secret, attributes)
+			}
+		}
+	}
+
+	snapshot := metrics.Snapshot()
+	if snapshot.Calls != 5 || snapshot.LatencySum <= 0 {
+		t.Errorf("Snapshot() = %+v, expected 5 calls", snapshot)
+	}
+	for _, outcome := range wantOutcomes {
+		if snapshot.Outcomes[outcome] != 1 {
+			t.Errorf("Snapshot().Outcomes[%s] = %d, want 1", outcome, snapshot.Outcomes[outcome])
+		}
+	}
+	if len(snapshot.Latency) != 3 || snapshot.Latency[1].UpperBound != time.Hour || snapshot.Latency[1].Count != 5 {
+		t.Errorf("Snapshot().Latency = %+v, expected all calls in the second bucket", snapshot.Latency)
+	}
+}
+
+// TestInstrumentationError tests that the call error doesn't leak the email address
+func TestInstrumentationError(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	server.Close()
+
+	rec := &recorder{}
+
+	api := newAPI(server, "")
+	api.instrumentation = rec
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, _ = api.Get(context.Background(), "john.doe@example.com")
+	_, _, _ = api.Get(ctx, "john.doe@example.com")
+
+	if len(rec.calls) != 2 {
+		t.Fatalf("recorded %d calls, want 2", len(rec.calls))
+	}
+
+	for i, call := range rec.calls {
+		if call.Err == nil || call.Outcome() != OutcomeError {
+			t.Errorf("call %d error = %v, outcome = %s, expected error", i, call.Err, call.Outcome())
+			continue
+		}
+		for _, secret := range []string{"john.doe", apiKey} {
+			if strings.Contains(call.Err.Error(), secret) {
+				t.Errorf("call %d error leaks %q: %v", i, secret, call.Err)
+			}
+		}
+	}
+
+	if !errors.Is(rec.calls[1].Err, context.Canceled) {
+		t.Errorf("call error = %v, want %v", rec.calls[1].Err, context.Canceled)
+	}
+}
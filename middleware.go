@@ -0,0 +1,72 @@
+package emailverifier
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Doer sends the API request and returns the API response
+type Doer interface {
+	Do(ctx context.Context, req *http.Request) (*Response, error)
+}
+
+// DoerFunc is an adapter to use ordinary functions as Doer
+type DoerFunc func(ctx context.Context, req *http.Request) (*Response, error)
+
+// Do calls f(ctx, req)
+func (f DoerFunc) Do(ctx context.Context, req *http.Request) (*Response, error) {
+	return f(ctx, req)
+}
+
+// Middleware wraps Doer to inspect or modify requests and responses.
+// The context passed to next is used for the request
+type Middleware func(next Doer) Doer
+
+// HeaderMiddleware returns Middleware setting the headers on every request
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+			req = req.Clone(ctx)
+			for k, v := range header {
+				req.Header[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+			}
+			return next.Do(ctx, req)
+		})
+	}
+}
+
+// TimingMiddleware returns Middleware calling observe with the duration of every request
+// The duration includes retries made by the client
+func TimingMiddleware(observe func(req *http.Request, resp *Response, err error, d time.Duration)) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+			start := time.Now()
+			resp, err := next.Do(ctx, req)
+			observe(req, resp, err, time.Since(start))
+			return resp, err
+		})
+	}
+}
+
+// LoggingMiddleware returns Middleware logging every request and its outcome with the API key redacted
+// If logger is nil then the standard logger is used
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return TimingMiddleware(func(req *http.Request, resp *Response, err error, d time.Duration) {
+		target := req.Method + " " + redactURL(req.URL.String())
+
+		switch {
+		case err != nil:
+			logger.Printf("%s failed in %v: %v", target, d, err)
+		case resp != nil && resp.Response != nil:
+			logger.Printf("%s: %s in %v, %d attempt(s)", target, resp.Status, d, resp.Attempts)
+		default:
+			logger.Printf("%s: no response in %v", target, d)
+		}
+	})
+}
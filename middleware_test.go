@@ -0,0 +1,143 @@
+package emailverifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMiddlewares tests the order of the middlewares and the context propagation
+func TestMiddlewares(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Trace", req.Header.Get("X-Trace"))
+		_, _ = w.Write([]byte(`{"emailAddress":"support@whoisxmlapi.com"}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type ctxKey string
+
+	var order []string
+
+	tracing := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+				order = append(order, name+" request")
+				ctx = context.WithValue(ctx, ctxKey(name), true)
+				req.Header.Set("X-Trace", strings.TrimPrefix(req.Header.Get("X-Trace")+","+name, ","))
+
+				resp, err := next.Do(ctx, req)
+
+				order = append(order, name+" response")
+				return resp, err
+			})
+		}
+	}
+
+	checkContext := func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+			if ctx.Value(ctxKey("outer")) == nil || ctx.Value(ctxKey("inner")) == nil {
+				return nil, errors.New("context values are not propagated")
+			}
+			return next.Do(ctx, req)
+		})
+	}
+
+	replace := func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *http.Request) (*Response, error) {
+			resp, err := next.Do(ctx, req)
+			if err == nil {
+				resp.Body = bytes.Replace(resp.Body, []byte("support"), []byte("sales"), 1)
+			}
+			return resp, err
+		})
+	}
+
+	api := NewClient(apiKey, ClientParams{
+		HTTPClient:   server.Client(),
+		EvapiBaseURL: apiURL,
+		Middlewares:  []Middleware{tracing("outer"), replace, tracing("inner"), checkContext},
+	})
+
+	evapiResp, resp, err := api.Get(context.Background(), "support@whoisxmlapi.com")
+	if err != nil {
+		t.Fatalf("Evapi.Get() error = %v", err)
+	}
+
+	if want := "outer request,inner request,inner response,outer response"; strings.Join(order, ",") != want {
+		t.Errorf("middlewares order = %v, want %v", order, want)
+	}
+	if got := resp.Header.Get("X-Trace"); got != "outer,inner" {
+		t.Errorf("X-Trace = %s, want outer,inner", got)
+	}
+	if evapiResp.EmailAddress != "sales@whoisxmlapi.com" {
+		t.Errorf("Evapi.Get() got = %s, expected response modified by the middleware", evapiResp.EmailAddress)
+	}
+}
+
+// TestBuiltinMiddlewares tests the header, timing and logging middlewares
+func TestBuiltinMiddlewares(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Tenant") != "acme" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"emailAddress":"support@whoisxmlapi.com"}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	var timings []time.Duration
+
+	api := NewClient(apiKey, ClientParams{
+		HTTPClient:   server.Client(),
+		EvapiBaseURL: apiURL,
+		Middlewares: []Middleware{
+			LoggingMiddleware(log.New(&logs, "", 0)),
+			TimingMiddleware(func(req *http.Request, resp *Response, err error, d time.Duration) {
+				timings = append(timings, d)
+			}),
+			HeaderMiddleware(http.Header{"x-tenant": {"acme"}}),
+		},
+	})
+
+	if _, _, err = api.Get(context.Background(), "support@whoisxmlapi.com"); err != nil {
+		t.Fatalf("Evapi.Get() error = %v", err)
+	}
+
+	server.Close()
+
+	if _, _, err = api.Get(context.Background(), "support@whoisxmlapi.com"); err == nil {
+		t.Fatalf("Evapi.Get() expected error")
+	}
+
+	if len(timings) != 2 || timings[0] <= 0 {
+		t.Errorf("timings = %v, expected 2 positive durations", timings)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], ": 200 OK in ") || !strings.Contains(lines[1], " failed in ") {
+		t.Errorf("logs = %s, expected success and failure", logs.String())
+	}
+	if strings.Contains(logs.String(), apiKey) || !strings.Contains(lines[0], "GET "+server.URL+"?apiKey=REDACTED") {
+		t.Errorf("logs = %s, expected redacted API key", logs.String())
+	}
+}
@@ -2,6 +2,7 @@ package emailverifier
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"strconv"
 	"time"
@@ -36,88 +37,143 @@ func (b StringBool) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + strconv.FormatBool(bool(b)) + `"`), nil
 }
 
+// UnmarshalXML decodes true/false values from Email Verification API
+func (b *StringBool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var str string
+	if err := d.DecodeElement(&str, &start); err != nil {
+		return err
+	}
+
+	*b = str == "true" || str == "1"
+	return nil
+}
+
+// MarshalXML encodes true/false values to the Email Verification API representation
+func (b StringBool) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(strconv.FormatBool(bool(b)), start)
+}
+
 // Time is a helper wrapper on time.Time
 type Time time.Time
 
 var emptyTime Time
 
+// timeLayout is the time format used by Email Verification API
+const timeLayout = "2006-01-02 15:04:05 MST"
+
+// parseTime parses time as Email Verification API formats it
+func parseTime(str string) (Time, error) {
+	if str == "" {
+		return emptyTime, nil
+	}
+	v, err := time.Parse(timeLayout, str)
+	if err != nil {
+		return emptyTime, err
+	}
+	return Time(v), nil
+}
+
+// String returns time as Email Verification API formats it
+func (t Time) String() string {
+	if t == emptyTime {
+		return ""
+	}
+	return time.Time(t).Format(timeLayout)
+}
+
 // UnmarshalJSON decodes time as Email Verification API does
 func (t *Time) UnmarshalJSON(b []byte) error {
 	str, err := unmarshalString(b)
 	if err != nil {
 		return err
 	}
-	if str == "" {
-		*t = emptyTime
-		return nil
-	}
-	v, err := time.Parse("2006-01-02 15:04:05 MST", str)
+	v, err := parseTime(str)
 	if err != nil {
 		return err
 	}
-	*t = Time(v)
+	*t = v
 	return nil
 }
 
 // MarshalJSON encodes time as Email Verification API does
 func (t Time) MarshalJSON() ([]byte, error) {
-	if t == emptyTime {
-		return []byte(`""`), nil
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+// UnmarshalXML decodes time as Email Verification API does
+func (t *Time) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var str string
+	if err := d.DecodeElement(&str, &start); err != nil {
+		return err
+	}
+	v, err := parseTime(str)
+	if err != nil {
+		return err
 	}
-	return []byte(`"` + time.Time(t).Format("2006-01-02 15:04:05 MST") + `"`), nil
+	*t = v
+	return nil
+}
+
+// MarshalXML encodes time as Email Verification API does
+func (t Time) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(t.String(), start)
 }
 
 // Audit is part of the Email Verification API response
 // It represents dates when data was added and updated in our database
 type Audit struct {
 	// AuditCreatedDate is the date this data is collected on whoisxmlapi.com
-	AuditCreatedDate Time `json:"auditCreatedDate"`
+	AuditCreatedDate Time `json:"auditCreatedDate" xml:"auditCreatedDate"`
 
 	// AuditUpdatedDate is the date this data is updated on whoisxmlapi.com
-	AuditUpdatedDate Time `json:"auditUpdatedDate"`
+	AuditUpdatedDate Time `json:"auditUpdatedDate" xml:"auditUpdatedDate"`
 }
 
 // EvapiResponse is a response of Email Verification API
 type EvapiResponse struct {
 	// Username is a username
-	Username string `json:"username"`
+	Username string `json:"username" xml:"username"`
 
 	// Domain is a domain name
-	Domain string `json:"domain"`
+	Domain string `json:"domain" xml:"domain"`
 
 	// EmailAddress is an email address
-	EmailAddress string `json:"emailAddress"`
+	EmailAddress string `json:"emailAddress" xml:"emailAddress"`
 
 	// FormatCheck indicates if there are any syntax errors in the email address
-	FormatCheck *StringBool `json:"formatCheck"`
+	FormatCheck *StringBool `json:"formatCheck" xml:"formatCheck"`
 
 	// SmtpCheck indicates if the email address exists and can receive emails by using SMTP connection and
 	// email-sending emulation techniques
-	SmtpCheck *StringBool `json:"smtpCheck"`
+	SmtpCheck *StringBool `json:"smtpCheck" xml:"smtpCheck"`
 
 	// DnsCheck ensures that the domain in the email address is a valid domain
-	DnsCheck *StringBool `json:"dnsCheck"`
+	DnsCheck *StringBool `json:"dnsCheck" xml:"dnsCheck"`
 
 	// FreeCheck indicates if the email address is from a free email provider
-	FreeCheck *StringBool `json:"freeCheck"`
+	FreeCheck *StringBool `json:"freeCheck" xml:"freeCheck"`
 
 	// DisposableCheck tells you whether the email address is disposable
-	DisposableCheck *StringBool `json:"disposableCheck"`
+	DisposableCheck *StringBool `json:"disposableCheck" xml:"disposableCheck"`
 
 	// CatchAllCheck tells you whether the related mail server has a "catch-all" address
-	CatchAllCheck *StringBool `json:"catchAllCheck"`
+	CatchAllCheck *StringBool `json:"catchAllCheck" xml:"catchAllCheck"`
 
 	// MxRecords is a mail servers list
-	MxRecords []string `json:"mxRecords"`
+	MxRecords []string `json:"mxRecords" xml:"mxRecords>item"`
 
 	// Audit is a data update dates
-	Audit Audit `json:"audit"`
+	Audit Audit `json:"audit" xml:"audit"`
+
+	// Suggestion is the corrected email address proposed when the format or DNS check failed
+	// It's set by the client with ClientParams.Suggester and never sent by the API
+	Suggestion string `json:"-" xml:"-"`
 }
 
 // ErrorMessage is an error message
 type ErrorMessage struct {
 	// Message is an error message
-	Message string `json:"Error"`
+	Message string `json:"Error" xml:"Error"`
 }
 
 // Error returns error message as a string
@@ -2,6 +2,7 @@ package emailverifier
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"testing"
 )
 
@@ -120,6 +121,62 @@ func TestStringBool(t *testing.T) {
 	}
 }
 
+// TestXML tests XML encoding/parsing functions for the time and bool values
+func TestXML(t *testing.T) {
+
+	type sample struct {
+		XMLName xml.Name    `xml:"sample"`
+		Bool    *StringBool `xml:"bool"`
+		Time    Time        `xml:"time"`
+	}
+
+	tests := []struct {
+		name   string
+		want   string
+		decErr string
+	}{
+		{
+			name: "<sample><bool>true</bool><time>2006-01-02 15:04:05 UTC</time></sample>",
+			want: "<sample><bool>true</bool><time>2006-01-02 15:04:05 UTC</time></sample>",
+		},
+		{
+			name: "<sample><bool>1</bool><time></time></sample>",
+			want: "<sample><bool>true</bool><time></time></sample>",
+		},
+		{
+			name: "<sample><bool>0</bool></sample>",
+			want: "<sample><bool>false</bool><time></time></sample>",
+		},
+		{
+			name: "<sample><time></time></sample>",
+			want: "<sample><time></time></sample>",
+		},
+		{
+			name:   "<sample><time>2006-01-02T15:04:05-07:00</time></sample>",
+			decErr: `parsing time "2006-01-02T15:04:05-07:00" as "2006-01-02 15:04:05 MST": cannot parse "T15:04:05-07:00" as " "`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var v sample
+
+			err := xml.Unmarshal([]byte(tt.name), &v)
+			checkErr(t, err, tt.decErr)
+			if tt.decErr != "" {
+				return
+			}
+
+			bb, err := xml.Marshal(v)
+			checkErr(t, err, "")
+
+			if string(bb) != tt.want {
+				t.Errorf("got = %v, want %v", string(bb), tt.want)
+			}
+		})
+	}
+}
+
 // checkErr checks for an error
 func checkErr(t *testing.T, err error, want string) {
 	if (err != nil || want != "") && (err == nil || err.Error() != want) {
@@ -0,0 +1,204 @@
+package emailverifier
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// MailProvider is the mail provider detected from the MX records
+type MailProvider string
+
+// Mail providers detected by ClassifyMX and EvapiResponse.MailProvider
+const (
+	ProviderGoogle     MailProvider = "google_workspace"
+	ProviderMicrosoft  MailProvider = "microsoft_365"
+	ProviderProofpoint MailProvider = "proofpoint"
+	ProviderMimecast   MailProvider = "mimecast"
+	ProviderBarracuda  MailProvider = "barracuda"
+	ProviderCisco      MailProvider = "cisco_secure_email"
+	ProviderZoho       MailProvider = "zoho"
+	ProviderYahoo      MailProvider = "yahoo"
+	ProviderYandex     MailProvider = "yandex"
+	ProviderAmazon     MailProvider = "amazon"
+	ProviderApple      MailProvider = "icloud"
+	ProviderFastmail   MailProvider = "fastmail"
+	ProviderProton     MailProvider = "proton"
+	ProviderGoDaddy    MailProvider = "godaddy"
+
+	// ProviderGmail is the consumer Gmail, unlike ProviderGoogle it's not a business mailbox
+	ProviderGmail MailProvider = "gmail"
+
+	// ProviderOutlook is the consumer Outlook.com (Hotmail, Live, MSN), unlike ProviderMicrosoft it's not a business mailbox
+	ProviderOutlook MailProvider = "outlook"
+
+	// ProviderSelfHosted means that the mail exchanger is in the same registered domain as the email address
+	ProviderSelfHosted MailProvider = "self_hosted"
+
+	// ProviderOther means that the mail exchanger is not recognized
+	ProviderOther MailProvider = "other"
+
+	// ProviderNone means that there are no MX records
+	ProviderNone MailProvider = "none"
+)
+
+// mxPatterns maps the mail exchanger domain suffixes to the providers. The first matching suffix wins,
+// so the consumer mail exchangers go before the business ones of the same company
+var mxPatterns = []struct {
+	suffix   string
+	provider MailProvider
+}{
+	{"gmail-smtp-in.l.google.com", ProviderGmail},
+	{"olc.protection.outlook.com", ProviderOutlook},
+	{"hotmail.com", ProviderOutlook},
+	{"google.com", ProviderGoogle},
+	{"googlemail.com", ProviderGoogle},
+	{"protection.outlook.com", ProviderMicrosoft},
+	{"pphosted.com", ProviderProofpoint},
+	{"ppe-hosted.com", ProviderProofpoint},
+	{"proofpoint.com", ProviderProofpoint},
+	{"mimecast.com", ProviderMimecast},
+	{"mimecast.co.za", ProviderMimecast},
+	{"mimecast-offshore.com", ProviderMimecast},
+	{"barracudanetworks.com", ProviderBarracuda},
+	{"iphmx.com", ProviderCisco},
+	{"zoho.com", ProviderZoho},
+	{"zoho.eu", ProviderZoho},
+	{"zohomail.com", ProviderZoho},
+	{"yahoodns.net", ProviderYahoo},
+	{"yandex.net", ProviderYandex},
+	{"yandex.ru", ProviderYandex},
+	{"amazonaws.com", ProviderAmazon},
+	{"icloud.com", ProviderApple},
+	{"messagingengine.com", ProviderFastmail},
+	{"protonmail.ch", ProviderProton},
+	{"secureserver.net", ProviderGoDaddy},
+}
+
+// MXRecord is the parsed mail exchanger record
+type MXRecord struct {
+	// Host is the lower-cased host name without the trailing dot
+	Host string `json:"host"`
+
+	// Preference is the record preference, lower values are preferred. It's nil if it's unknown
+	Preference *uint16 `json:"preference,omitempty"`
+
+	// Provider is the mail provider of the host, ProviderOther if it's not recognized
+	Provider MailProvider `json:"provider"`
+}
+
+// ParseMXRecords parses the MX records as the API reports them: host names with the trailing dot,
+// optionally preceded by the preference, e.g. "10 mx.example.com.". The records are sorted by preference
+// keeping the original order of the records with equal or unknown preference
+func ParseMXRecords(records []string) []MXRecord {
+	var parsed []MXRecord
+
+	for _, record := range records {
+		fields := strings.Fields(record)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var mx MXRecord
+		if len(fields) > 1 {
+			if pref, err := strconv.ParseUint(fields[0], 10, 16); err == nil {
+				preference := uint16(pref)
+				mx.Preference = &preference
+				fields = fields[1:]
+			}
+		}
+
+		mx.Host = normalizeHost(fields[0])
+		if mx.Host == "" {
+			continue
+		}
+		mx.Provider = ClassifyMX(mx.Host)
+
+		parsed = append(parsed, mx)
+	}
+
+	sortMXRecords(parsed)
+
+	return parsed
+}
+
+// sortMXRecords sorts the records by preference, records with unknown preference go last
+func sortMXRecords(records []MXRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		pi, pj := records[i].Preference, records[j].Preference
+		switch {
+		case pi == nil:
+			return false
+		case pj == nil:
+			return true
+		}
+		return *pi < *pj
+	})
+}
+
+// normalizeHost returns the lower-cased host name without the trailing dot
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+}
+
+// ClassifyMX returns the mail provider of the mail exchanger host or ProviderOther if it's not recognized
+func ClassifyMX(host string) MailProvider {
+	host = normalizeHost(host)
+
+	for _, p := range mxPatterns {
+		if host == p.suffix || strings.HasSuffix(host, "."+p.suffix) {
+			return p.provider
+		}
+	}
+
+	return ProviderOther
+}
+
+// ClassifyMailProvider returns the mail provider of the domain with the MX records
+// The most preferred record decides, a mail exchanger in the same registered domain is self-hosted
+func ClassifyMailProvider(domain string, records []MXRecord) MailProvider {
+	if len(records) == 0 {
+		return ProviderNone
+	}
+
+	primary := records[0]
+
+	provider := primary.Provider
+	if provider == "" {
+		provider = ClassifyMX(primary.Host)
+	}
+	if provider != ProviderOther {
+		return provider
+	}
+
+	if sameRegisteredDomain(normalizeHost(domain), normalizeHost(primary.Host)) {
+		return ProviderSelfHosted
+	}
+
+	return ProviderOther
+}
+
+// sameRegisteredDomain reports whether the hosts belong to the same registered domain, e.g. example.co.uk
+func sameRegisteredDomain(a, b string) bool {
+	ra, err := publicsuffix.EffectiveTLDPlusOne(a)
+	if err != nil {
+		return false
+	}
+	rb, err := publicsuffix.EffectiveTLDPlusOne(b)
+	if err != nil {
+		return false
+	}
+	return ra == rb
+}
+
+// MX returns the parsed MX records of the response
+func (r *EvapiResponse) MX() []MXRecord {
+	return ParseMXRecords(r.MxRecords)
+}
+
+// MailProvider returns the mail provider detected from the MX records of the response
+func (r *EvapiResponse) MailProvider() MailProvider {
+	return ClassifyMailProvider(responseDomain(r), r.MX())
+}
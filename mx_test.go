@@ -0,0 +1,89 @@
+package emailverifier
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// TestParseMXRecords tests parsing of the MX records
+func TestParseMXRecords(t *testing.T) {
+	pref := func(v uint16) *uint16 { return &v }
+
+	got := ParseMXRecords([]string{
+		"alt1.aspmx.l.google.com.", "20 ALT2.ASPMX.L.GOOGLE.COM.", "10 aspmx.l.google.com.", "", "mx.example.com",
+	})
+	want := []MXRecord{
+		{Host: "aspmx.l.google.com", Preference: pref(10), Provider: ProviderGoogle},
+		{Host: "alt2.aspmx.l.google.com", Preference: pref(20), Provider: ProviderGoogle},
+		{Host: "alt1.aspmx.l.google.com", Provider: ProviderGoogle},
+		{Host: "mx.example.com", Provider: ProviderOther},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseMXRecords() = %+v, want %+v", got, want)
+	}
+
+	if got := ParseMXRecords(nil); len(got) != 0 {
+		t.Errorf("ParseMXRecords(nil) = %+v", got)
+	}
+}
+
+// TestMailProvider tests the mail provider detection
+func TestMailProvider(t *testing.T) {
+	tests := []struct {
+		domain    string
+		mxRecords []string
+		want      MailProvider
+	}{
+		{"whoisxmlapi.com", []string{"alt1.aspmx.l.google.com.", "aspmx.l.google.com."}, ProviderGoogle},
+		{"example.com", []string{"example-com.mail.protection.outlook.com."}, ProviderMicrosoft},
+		{"gmail.com", []string{"5 gmail-smtp-in.l.google.com.", "10 alt1.gmail-smtp-in.l.google.com."}, ProviderGmail},
+		{"googlemail.com", []string{"alt2.gmail-smtp-in.l.google.com."}, ProviderGmail},
+		{"hotmail.com", []string{"hotmail-com.olc.protection.outlook.com."}, ProviderOutlook},
+		{"outlook.com", []string{"outlook-com.olc.protection.outlook.com."}, ProviderOutlook},
+		{"example.com", []string{"mx0a-001234.pphosted.com."}, ProviderProofpoint},
+		{"example.com", []string{"eu-smtp-inbound-1.mimecast.com."}, ProviderMimecast},
+		{"example.com", []string{"mx1.hc1234-56.iphmx.com."}, ProviderCisco},
+		{"example.com", []string{"mx.zoho.eu."}, ProviderZoho},
+		{"example.co.uk", []string{"mail.example.co.uk."}, ProviderSelfHosted},
+		{"sub.example.co.uk", []string{"mx.example.co.uk."}, ProviderSelfHosted},
+		{"example.co.uk", []string{"mx.other.co.uk."}, ProviderOther},
+		{"example.com", []string{"20 mx2.example.com.", "10 aspmx.l.google.com."}, ProviderGoogle},
+		{"example.com", []string{"10 mx.example.com.", "20 aspmx.l.google.com."}, ProviderSelfHosted},
+		{"example.com", nil, ProviderNone},
+	}
+	for _, tt := range tests {
+		r := &EvapiResponse{EmailAddress: "john@" + tt.domain, MxRecords: tt.mxRecords}
+		if got := r.MailProvider(); got != tt.want {
+			t.Errorf("MailProvider(%s, %v) = %v, want %v", tt.domain, tt.mxRecords, got, tt.want)
+		}
+	}
+
+	if got := ClassifyMX("notgoogle.com"); got != ProviderOther {
+		t.Errorf("ClassifyMX() = %v, want %v", got, ProviderOther)
+	}
+}
+
+// TestLookupMXRecords tests the MX records with preferences from the DNS checker
+func TestLookupMXRecords(t *testing.T) {
+	resolver := newFakeResolver()
+	checker := NewDNSChecker(resolver)
+
+	records, err := checker.LookupMXRecords(context.Background(), "whoisxmlapi.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Host != "aspmx.l.google.com" || records[0].Preference == nil ||
+		*records[0].Preference != 1 || records[1].Provider != ProviderGoogle {
+		t.Errorf("LookupMXRecords() = %+v", records)
+	}
+
+	records, err = checker.LookupMXRecords(context.Background(), "a-only.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Preference != nil ||
+		ClassifyMailProvider("a-only.example", records) != ProviderSelfHosted {
+		t.Errorf("LookupMXRecords() = %+v, expected implicit MX", records)
+	}
+}
@@ -0,0 +1,349 @@
+package emailverifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Decision is the acceptance policy decision
+type Decision string
+
+// Policy decisions
+const (
+	DecisionAccept Decision = "accept"
+	DecisionReject Decision = "reject"
+	DecisionReview Decision = "review"
+)
+
+// Policy reason codes
+const (
+	// ReasonDomainAllowed means that the domain is in the allow list
+	ReasonDomainAllowed = "domain_allowed"
+
+	// ReasonDomainDenied means that the domain is in the deny list
+	ReasonDomainDenied = "domain_denied"
+
+	// ReasonCheck means that the rule matched the check status
+	ReasonCheck = "check"
+
+	// ReasonVerdict means that the rule matched the verdict
+	ReasonVerdict = "verdict"
+
+	// ReasonDefault means that no rule matched
+	ReasonDefault = "default"
+)
+
+// Policy is the acceptance policy deciding whether to accept, reject or send the address to manual review
+// The deny list is checked first, then the allow list, then the rules in order. The first matching rule wins.
+// Policies can be loaded from JSON or YAML with ParsePolicy and LoadPolicy, e.g.:
+//
+//	allowDomains: [partner.com]
+//	denyDomains: [competitor.com]
+//	rules:
+//	  - name: no-disposable
+//	    when: {disposableCheck: flagged}
+//	    decision: reject
+//	  - name: free-b2b
+//	    when: {freeCheck: flagged}
+//	    exceptTags: [b2c]
+//	    decision: reject
+//	  - name: catch-all
+//	    when: {catchAllCheck: flagged}
+//	    decision: review
+//	  - name: smtp-unknown
+//	    when: {smtpCheck: unchecked, dnsCheck: passed}
+//	    decision: accept
+//	  - name: undeliverable
+//	    verdicts: [undeliverable, unknown]
+//	    decision: reject
+//	default: accept
+type Policy struct {
+	// AllowDomains are the domains accepted without evaluating the rules. Subdomains match as well
+	AllowDomains []string `json:"allowDomains,omitempty" yaml:"allowDomains,omitempty"`
+
+	// DenyDomains are the domains rejected without evaluating the rules. Subdomains match as well
+	DenyDomains []string `json:"denyDomains,omitempty" yaml:"denyDomains,omitempty"`
+
+	// Rules are evaluated in order, the first matching rule makes the decision
+	Rules []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+
+	// Default is the decision if no rule matches. Default: accept.
+	Default Decision `json:"default,omitempty" yaml:"default,omitempty"`
+
+	// Scoring is the scoring policy used for the rule verdicts. Default: DefaultScoringPolicy.
+	Scoring *ScoringPolicy `json:"scoring,omitempty" yaml:"scoring,omitempty"`
+}
+
+// Rule is the acceptance policy rule. It matches if all its conditions are met
+type Rule struct {
+	// Name is the rule name reported in the reasons
+	Name string `json:"name" yaml:"name"`
+
+	// When is the required status of the checks
+	When map[Check]CheckStatus `json:"when,omitempty" yaml:"when,omitempty"`
+
+	// Verdicts matches if the verdict is any of them
+	Verdicts []Verdict `json:"verdicts,omitempty" yaml:"verdicts,omitempty"`
+
+	// Tags matches if the evaluation has any of the tags, e.g. the tenant type
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// ExceptTags matches if the evaluation has none of the tags
+	ExceptTags []string `json:"exceptTags,omitempty" yaml:"exceptTags,omitempty"`
+
+	// Decision is the decision made by the rule
+	Decision Decision `json:"decision" yaml:"decision"`
+}
+
+// PolicyReason is the machine-readable reason of the decision
+type PolicyReason struct {
+	// Code is the reason code: domain_allowed | domain_denied | check | verdict | default
+	Code string `json:"code"`
+
+	// Rule is the name of the matched rule
+	Rule string `json:"rule,omitempty"`
+
+	// Domain is the matched allow or deny list domain
+	Domain string `json:"domain,omitempty"`
+
+	// Check is the matched check
+	Check Check `json:"check,omitempty"`
+
+	// Status is the matched check status
+	Status CheckStatus `json:"status,omitempty"`
+
+	// Verdict is the matched verdict
+	Verdict Verdict `json:"verdict,omitempty"`
+}
+
+// PolicyResult is the result of the policy evaluation
+type PolicyResult struct {
+	// Decision is the decision
+	Decision Decision `json:"decision"`
+
+	// Reasons are the reasons of the decision
+	Reasons []PolicyReason `json:"reasons"`
+}
+
+// Evaluate evaluates the response with the policy
+// The tags describe the evaluation context, e.g. the tenant type, and are matched by Rule.Tags and Rule.ExceptTags
+func (p *Policy) Evaluate(r *EvapiResponse, tags ...string) PolicyResult {
+	domain := responseDomain(r)
+
+	if d, ok := matchDomain(domain, p.DenyDomains); ok {
+		return PolicyResult{
+			Decision: DecisionReject,
+			Reasons:  []PolicyReason{{Code: ReasonDomainDenied, Domain: d}},
+		}
+	}
+
+	if d, ok := matchDomain(domain, p.AllowDomains); ok {
+		return PolicyResult{
+			Decision: DecisionAccept,
+			Reasons:  []PolicyReason{{Code: ReasonDomainAllowed, Domain: d}},
+		}
+	}
+
+	for i := range p.Rules {
+		if reasons, ok := p.Rules[i].match(r, p.Scoring, tags); ok {
+			return PolicyResult{Decision: p.Rules[i].Decision, Reasons: reasons}
+		}
+	}
+
+	decision := p.Default
+	if decision == "" {
+		decision = DecisionAccept
+	}
+
+	return PolicyResult{
+		Decision: decision,
+		Reasons:  []PolicyReason{{Code: ReasonDefault}},
+	}
+}
+
+// match returns the reasons if the rule matches the response
+func (rule *Rule) match(r *EvapiResponse, scoring *ScoringPolicy, tags []string) ([]PolicyReason, bool) {
+	if len(rule.Tags) > 0 && !anyTag(rule.Tags, tags) {
+		return nil, false
+	}
+	if anyTag(rule.ExceptTags, tags) {
+		return nil, false
+	}
+
+	var reasons []PolicyReason
+
+	for _, check := range allChecks {
+		want, ok := rule.When[check]
+		if !ok {
+			continue
+		}
+		if check.Status(r) != want {
+			return nil, false
+		}
+		reasons = append(reasons, PolicyReason{Code: ReasonCheck, Rule: rule.Name, Check: check, Status: want})
+	}
+
+	if len(rule.Verdicts) > 0 {
+		verdict := scoring.Verdict(r)
+		matched := false
+		for _, v := range rule.Verdicts {
+			matched = matched || v == verdict
+		}
+		if !matched {
+			return nil, false
+		}
+		reasons = append(reasons, PolicyReason{Code: ReasonVerdict, Rule: rule.Name, Verdict: verdict})
+	}
+
+	return reasons, true
+}
+
+// anyTag reports whether any of the tags is in the list
+func anyTag(list, tags []string) bool {
+	for _, a := range list {
+		for _, b := range tags {
+			if a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// responseDomain returns the lower-case domain of the response
+func responseDomain(r *EvapiResponse) string {
+	domain := r.Domain
+	if domain == "" {
+		if i := strings.LastIndexByte(r.EmailAddress, '@'); i >= 0 {
+			domain = r.EmailAddress[i+1:]
+		}
+	}
+	return strings.TrimSuffix(strings.ToLower(domain), ".")
+}
+
+// matchDomain returns the list domain which is the domain or its parent
+func matchDomain(domain string, list []string) (string, bool) {
+	if domain == "" {
+		return "", false
+	}
+
+	for _, d := range list {
+		d = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" && (domain == d || strings.HasSuffix(domain, "."+d)) {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+// Validate checks that the policy has valid decisions, checks, statuses and verdicts
+func (p *Policy) Validate() error {
+	if p.Default != "" && !validDecision(p.Default) {
+		return &ArgError{"default", fmt.Sprintf("unknown decision %q", p.Default)}
+	}
+
+	for i, rule := range p.Rules {
+		name := fmt.Sprintf("rules[%d]", i)
+
+		if !validDecision(rule.Decision) {
+			return &ArgError{name, fmt.Sprintf("unknown decision %q", rule.Decision)}
+		}
+
+		for check, status := range rule.When {
+			if !validCheck(check) {
+				return &ArgError{name, fmt.Sprintf("unknown check %q", check)}
+			}
+			if status != CheckPassed && status != CheckFlagged && status != CheckUnchecked {
+				return &ArgError{name, fmt.Sprintf("unknown status %q of %s", status, check)}
+			}
+		}
+
+		for _, v := range rule.Verdicts {
+			switch v {
+			case VerdictDeliverable, VerdictRisky, VerdictUndeliverable, VerdictUnknown:
+			default:
+				return &ArgError{name, fmt.Sprintf("unknown verdict %q", v)}
+			}
+		}
+	}
+
+	if p.Scoring != nil {
+		for check := range p.Scoring.Weights {
+			if !validCheck(check) {
+				return &ArgError{"scoring", fmt.Sprintf("unknown check %q", check)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validDecision reports whether the decision is known
+func validDecision(d Decision) bool {
+	return d == DecisionAccept || d == DecisionReject || d == DecisionReview
+}
+
+// validCheck reports whether the check is known
+func validCheck(c Check) bool {
+	for _, check := range allChecks {
+		if c == check {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePolicy parses the policy from JSON or YAML and validates it
+// Empty input is rejected, so a truncated policy file doesn't silently accept everything
+func ParsePolicy(data []byte) (*Policy, error) {
+	var policy Policy
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, &ArgError{"data", "cannot be empty"}
+	}
+
+	if trimmed[0] == '{' {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&policy); err != nil {
+			return nil, fmt.Errorf("cannot parse policy: %w", err)
+		}
+		if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+			return nil, errors.New("cannot parse policy: unexpected data after the policy")
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&policy); errors.Is(err, io.EOF) {
+			return nil, &ArgError{"data", "cannot be empty"}
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot parse policy: %w", err)
+		}
+		if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+			return nil, errors.New("cannot parse policy: unexpected data after the policy")
+		}
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// LoadPolicy reads the policy from the JSON or YAML file
+func LoadPolicy(name string) (*Policy, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read policy: %w", err)
+	}
+	return ParsePolicy(data)
+}
@@ -0,0 +1,210 @@
+package emailverifier
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestPolicy tests the policy evaluation
+func TestPolicy(t *testing.T) {
+	yes, no := boolPtr(true), boolPtr(false)
+
+	policy, err := LoadPolicy("testdata/policy.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withDomain := func(r *EvapiResponse, domain string) *EvapiResponse {
+		r.EmailAddress = "user@" + domain
+		return r
+	}
+
+	tests := []struct {
+		name     string
+		response *EvapiResponse
+		tags     []string
+		want     PolicyResult
+	}{
+		{
+			name:     "denied domain",
+			response: withDomain(checks(yes, yes, yes, no, no, no), "Mail.Competitor.com"),
+			want: PolicyResult{DecisionReject, []PolicyReason{
+				{Code: ReasonDomainDenied, Domain: "competitor.com"},
+			}},
+		},
+		{
+			name:     "allowed domain",
+			response: withDomain(checks(yes, yes, nil, yes, yes, no), "partner.com"),
+			want: PolicyResult{DecisionAccept, []PolicyReason{
+				{Code: ReasonDomainAllowed, Domain: "partner.com"},
+			}},
+		},
+		{
+			name:     "not a subdomain",
+			response: withDomain(checks(yes, yes, yes, no, no, no), "notpartner.com"),
+			want:     PolicyResult{DecisionAccept, []PolicyReason{{Code: ReasonDefault}}},
+		},
+		{
+			name:     "disposable",
+			response: checks(yes, yes, yes, no, yes, no),
+			want: PolicyResult{DecisionReject, []PolicyReason{
+				{Code: ReasonCheck, Rule: "no-disposable", Check: CheckDisposable, Status: CheckFlagged},
+			}},
+		},
+		{
+			name:     "free B2B",
+			response: checks(yes, yes, yes, no, no, yes),
+			want: PolicyResult{DecisionReject, []PolicyReason{
+				{Code: ReasonCheck, Rule: "free-b2b", Check: CheckFree, Status: CheckFlagged},
+			}},
+		},
+		{
+			name:     "free B2C",
+			response: checks(yes, yes, yes, no, no, yes),
+			tags:     []string{"b2c"},
+			want:     PolicyResult{DecisionAccept, []PolicyReason{{Code: ReasonDefault}}},
+		},
+		{
+			name:     "catch-all",
+			response: checks(yes, yes, yes, yes, no, no),
+			want: PolicyResult{DecisionReview, []PolicyReason{
+				{Code: ReasonCheck, Rule: "catch-all", Check: CheckCatchAll, Status: CheckFlagged},
+			}},
+		},
+		{
+			name:     "SMTP unknown",
+			response: checks(yes, yes, nil, no, no, no),
+			want: PolicyResult{DecisionAccept, []PolicyReason{
+				{Code: ReasonCheck, Rule: "smtp-unknown", Check: CheckDNS, Status: CheckPassed},
+				{Code: ReasonCheck, Rule: "smtp-unknown", Check: CheckSMTP, Status: CheckUnchecked},
+			}},
+		},
+		{
+			name:     "SMTP failed",
+			response: checks(yes, yes, no, no, no, no),
+			want: PolicyResult{DecisionReject, []PolicyReason{
+				{Code: ReasonVerdict, Rule: "undeliverable", Verdict: VerdictUndeliverable},
+			}},
+		},
+		{
+			name:     "DNS and SMTP unknown",
+			response: checks(yes, nil, nil, no, no, no),
+			want: PolicyResult{DecisionReject, []PolicyReason{
+				{Code: ReasonVerdict, Rule: "undeliverable", Verdict: VerdictUnknown},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Evaluate(tt.response, tt.tags...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParsePolicy tests parsing and validation of the policy
+func TestParsePolicy(t *testing.T) {
+	yamlPolicy, err := LoadPolicy("testdata/policy.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(yamlPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonPolicy, err := ParsePolicy(data)
+	if err != nil {
+		t.Fatalf("ParsePolicy() error = %v", err)
+	}
+	if !reflect.DeepEqual(jsonPolicy, yamlPolicy) {
+		t.Errorf("ParsePolicy() = %+v, want %+v", jsonPolicy, yamlPolicy)
+	}
+
+	empty, err := ParsePolicy([]byte("{}"))
+	if err != nil {
+		t.Fatalf("ParsePolicy() error = %v", err)
+	}
+	if got := empty.Evaluate(&EvapiResponse{}); got.Decision != DecisionAccept {
+		t.Errorf("empty policy Evaluate() = %v, want %v", got.Decision, DecisionAccept)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{
+			name: "unknown decision",
+			data: `{"rules":[{"name":"a","decision":"block"}]}`,
+			err:  `invalid argument: "rules[0]" unknown decision "block"`,
+		},
+		{
+			name: "unknown check",
+			data: "rules:\n  - when: {mxCheck: flagged}\n    decision: reject\n",
+			err:  `invalid argument: "rules[0]" unknown check "mxCheck"`,
+		},
+		{
+			name: "unknown status",
+			data: "rules:\n  - when: {smtpCheck: false}\n    decision: reject\n",
+			err:  `invalid argument: "rules[0]" unknown status "false" of smtpCheck`,
+		},
+		{
+			name: "unknown verdict",
+			data: "rules:\n  - verdicts: [bad]\n    decision: reject\n",
+			err:  `invalid argument: "rules[0]" unknown verdict "bad"`,
+		},
+		{
+			name: "unknown default",
+			data: "default: maybe\n",
+			err:  `invalid argument: "default" unknown decision "maybe"`,
+		},
+		{
+			name: "unknown field",
+			data: `{"rule":[]}`,
+			err:  `cannot parse policy: json: unknown field "rule"`,
+		},
+		{
+			name: "empty",
+			data: "",
+			err:  `invalid argument: "data" cannot be empty`,
+		},
+		{
+			name: "whitespace",
+			data: " \n\t\n",
+			err:  `invalid argument: "data" cannot be empty`,
+		},
+		{
+			name: "comments only",
+			data: "# default: reject\n",
+			err:  `invalid argument: "data" cannot be empty`,
+		},
+		{
+			name: "trailing JSON data",
+			data: `{"default":"reject"} garbage`,
+			err:  "cannot parse policy: unexpected data after the policy",
+		},
+		{
+			name: "several YAML documents",
+			data: "default: reject\n---\ndefault: accept\n",
+			err:  "cannot parse policy: unexpected data after the policy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.data))
+			checkErr(t, err, tt.err)
+
+			var argErr *ArgError
+			if !strings.HasPrefix(tt.err, "cannot parse") && !errors.As(err, &argErr) {
+				t.Errorf("ParsePolicy() error = %v, expected ArgError", err)
+			}
+		})
+	}
+}
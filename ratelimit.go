@@ -0,0 +1,143 @@
+package emailverifier
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiter of outgoing API requests.
+// It is safe for concurrent use and can be shared between several clients that use the same API key
+type RateLimiter struct {
+	mu sync.Mutex
+
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	waiting    int
+	requests   uint64
+	delayed    uint64
+	totalDelay time.Duration
+
+	now func() time.Time
+}
+
+// RateLimiterState is a snapshot of the RateLimiter state
+type RateLimiterState struct {
+	// Rate is the number of requests allowed per second
+	Rate float64
+
+	// Burst is the maximum number of requests allowed at once
+	Burst int
+
+	// Tokens is the number of requests which can be sent immediately. It's negative when requests are queued
+	Tokens float64
+
+	// Waiting is the number of requests currently waiting for their turn
+	Waiting int
+
+	// Requests is the total number of requests which passed the limiter
+	Requests uint64
+
+	// Delayed is the total number of requests which had to wait
+	Delayed uint64
+
+	// TotalDelay is the total time requests spent waiting
+	TotalDelay time.Duration
+}
+
+// NewRateLimiter creates RateLimiter allowing rps requests per second with bursts of at most burst requests
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if rps <= 0 {
+		panic("emailverifier: rate limiter requires positive rps")
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// advance refills the bucket according to the time passed. Must be called with the lock held
+func (l *RateLimiter) advance(now time.Time) {
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+}
+
+// Wait blocks until the request is allowed to be sent or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	l.advance(now)
+	l.tokens--
+
+	if l.tokens >= 0 {
+		l.requests++
+		l.mu.Unlock()
+		return nil
+	}
+
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		l.tokens++
+		l.mu.Unlock()
+		return fmt.Errorf("rate limit wait %v exceeds context deadline: %w", delay, context.DeadlineExceeded)
+	}
+	l.waiting++
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.waiting--
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	l.mu.Lock()
+	l.waiting--
+	l.requests++
+	l.delayed++
+	l.totalDelay += delay
+	l.mu.Unlock()
+
+	return nil
+}
+
+// State returns the current state of the limiter
+func (l *RateLimiter) State() RateLimiterState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(l.now())
+
+	return RateLimiterState{
+		Rate:       l.rate,
+		Burst:      int(l.burst),
+		Tokens:     l.tokens,
+		Waiting:    l.waiting,
+		Requests:   l.requests,
+		Delayed:    l.delayed,
+		TotalDelay: l.totalDelay,
+	}
+}
@@ -0,0 +1,173 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRateLimiter tests the token bucket behavior
+func TestRateLimiter(t *testing.T) {
+
+	now := time.Date(2022, 4, 3, 5, 2, 37, 0, time.UTC)
+
+	l := NewRateLimiter(10, 2)
+	l.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+
+	state := l.State()
+	if state.Tokens != 0 || state.Requests != 2 || state.Delayed != 0 {
+		t.Errorf("State() = %+v, expected burst to be spent without delay", state)
+	}
+
+	now = now.Add(100 * time.Millisecond)
+	if state = l.State(); state.Tokens != 1 {
+		t.Errorf("State().Tokens = %v, want 1", state.Tokens)
+	}
+
+	now = now.Add(time.Hour)
+	if state = l.State(); state.Tokens != 2 {
+		t.Errorf("State().Tokens = %v, want burst size 2", state.Tokens)
+	}
+}
+
+// TestRateLimiterWait tests waiting for a token and context cancellation
+func TestRateLimiterWait(t *testing.T) {
+
+	l := NewRateLimiter(20, 1)
+
+	ctx := context.Background()
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+
+	if d := time.Since(start); d < 90*time.Millisecond {
+		t.Errorf("3 requests at 20 rps took %v, expected at least 100ms", d)
+	}
+
+	state := l.State()
+	if state.Requests != 3 || state.Delayed != 2 || state.TotalDelay <= 0 || state.Waiting != 0 {
+		t.Errorf("State() = %+v, expected 2 delayed requests", state)
+	}
+
+	l = NewRateLimiter(1, 1)
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctxDeadline, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctxDeadline); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	ctxCancel, cancel := context.WithCancel(ctx)
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	if err := l.Wait(ctxCancel); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() error = %v, want %v", err, context.Canceled)
+	}
+
+	if state = l.State(); state.Waiting != 0 || state.Tokens < -0.01 {
+		t.Errorf("State() = %+v, expected cancelled requests to return their tokens", state)
+	}
+}
+
+// TestRateLimiterShared tests a limiter shared between several clients
+func TestRateLimiterShared(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"emailAddress":"support@whoisxmlapi.com"}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limiter := NewRateLimiter(50, 1)
+	params := ClientParams{
+		HTTPClient:   server.Client(),
+		EvapiBaseURL: apiURL,
+		RateLimiter:  limiter,
+	}
+	clients := []*Client{NewClient(apiKey, params), NewClient(apiKey, params)}
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(c *Client) {
+			defer wg.Done()
+			if _, err := c.GetRaw(context.Background(), "support@whoisxmlapi.com"); err != nil {
+				t.Errorf("Evapi.GetRaw() error = %v", err)
+			}
+		}(clients[i%2])
+	}
+	wg.Wait()
+
+	if d := time.Since(start); d < 90*time.Millisecond {
+		t.Errorf("6 requests at 50 rps took %v, expected at least 100ms", d)
+	}
+
+	if state := limiter.State(); state.Requests != 6 || state.Delayed != 5 {
+		t.Errorf("State() = %+v, expected 6 requests and 5 delayed", state)
+	}
+}
+
+// TestRateLimiterRetry tests that every retry attempt passes the limiter
+func TestRateLimiterRetry(t *testing.T) {
+
+	server, calls := flakyServer(2, http.StatusTooManyRequests, "", `{"emailAddress":"support@whoisxmlapi.com"}`)
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limiter := NewRateLimiter(50, 1)
+	api := NewClient(apiKey, ClientParams{
+		HTTPClient:     server.Client(),
+		EvapiBaseURL:   apiURL,
+		AccountBaseURL: apiURL,
+		RetryPolicy:    &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		RateLimiter:    limiter,
+	})
+
+	resp, err := api.GetRaw(context.Background(), "support@whoisxmlapi.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Attempts != 3 || *calls != 3 {
+		t.Errorf("Evapi.GetRaw() attempts = %d, server calls = %d, want 3", resp.Attempts, *calls)
+	}
+
+	if state := limiter.State(); state.Requests != 3 || state.Delayed != 2 {
+		t.Errorf("State() = %+v, expected 3 requests and 2 delayed", state)
+	}
+
+	_, _, _ = api.AccountService.Balance(context.Background())
+	if state := limiter.State(); state.Requests != 4 {
+		t.Errorf("State().Requests = %d, expected Balance to pass the limiter", state.Requests)
+	}
+}
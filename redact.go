@@ -0,0 +1,96 @@
+package emailverifier
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// apiKeyHeader is the header used to send the API key when APIKeyInHeader is set
+const apiKeyHeader = "X-Authentication-Token"
+
+// redacted replaces the API key in URLs and errors
+const redacted = "REDACTED"
+
+// APIKeyLocation defines where the API key is sent in GET requests
+type APIKeyLocation int
+
+const (
+	// APIKeyInQuery sends the API key in the apiKey query parameter
+	APIKeyInQuery APIKeyLocation = iota
+
+	// APIKeyInHeader sends the API key in the X-Authentication-Token header
+	APIKeyInHeader
+)
+
+// apiKeyPattern matches the apiKey query parameter value
+var apiKeyPattern = regexp.MustCompile(`(?i)(apiKey=)[^&#\s"]*`)
+
+// authorize adds the API key to the GET request
+func (c *Client) authorize(req *http.Request) {
+	if c.apiKeyLocation == APIKeyInHeader {
+		req.Header.Set(apiKeyHeader, c.apiKey)
+		return
+	}
+
+	query := req.URL.Query()
+	query.Set("apiKey", c.apiKey)
+	req.URL.RawQuery = query.Encode()
+}
+
+// redactURL returns the URL with the API key replaced
+func redactURL(u string) string {
+	return apiKeyPattern.ReplaceAllString(u, "${1}"+redacted)
+}
+
+// redactError returns the error with the API key removed from the request URL
+func redactError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+
+	return &url.Error{
+		Op:  urlErr.Op,
+		URL: redactURL(urlErr.URL),
+		Err: urlErr.Err,
+	}
+}
+
+// String returns the response description with the API key redacted
+func (r *Response) String() string {
+	if r == nil {
+		return "<nil>"
+	}
+
+	var b strings.Builder
+
+	switch {
+	case r.Response == nil:
+		b.WriteString("no response")
+	default:
+		b.WriteString(r.Status)
+		if r.Request != nil && r.Request.URL != nil {
+			b.WriteString(" " + r.Request.Method + " " + redactURL(r.Request.URL.String()))
+		}
+	}
+
+	var notes []string
+	if r.Attempts > 1 {
+		notes = append(notes, strconv.Itoa(r.Attempts)+" attempts")
+	}
+	if r.CacheHit {
+		notes = append(notes, "cache hit")
+	}
+	if r.Synthesized {
+		notes = append(notes, "synthesized")
+	}
+	if len(notes) > 0 {
+		b.WriteString(" (" + strings.Join(notes, ", ") + ")")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestRedactURL tests removing of the API key from URLs
+func TestRedactURL(t *testing.T) {
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{
+			url:  "https://emailverification.whoisxmlapi.com/api/v2?apiKey=at_secret&emailAddress=a%40b.com",
+			want: "https://emailverification.whoisxmlapi.com/api/v2?apiKey=REDACTED&emailAddress=a%40b.com",
+		},
+		{
+			url:  "https://emailverification.whoisxmlapi.com/api/v2?emailAddress=a%40b.com&APIKEY=at_secret",
+			want: "https://emailverification.whoisxmlapi.com/api/v2?emailAddress=a%40b.com&APIKEY=REDACTED",
+		},
+		{
+			url:  "https://emailverification.whoisxmlapi.com/api/v2?emailAddress=a%40b.com",
+			want: "https://emailverification.whoisxmlapi.com/api/v2?emailAddress=a%40b.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := redactURL(tt.url); got != tt.want {
+				t.Errorf("redactURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRedactError tests that errors and responses don't leak the API key
+func TestRedactError(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"emailAddress":"support@whoisxmlapi.com"}`))
+	}))
+
+	api := newAPI(server, "")
+	ctx := context.Background()
+
+	_, resp, err := api.Get(ctx, "support@whoisxmlapi.com")
+	if err != nil {
+		t.Fatalf("Evapi.Get() error = %v", err)
+	}
+
+	for _, s := range []string{resp.String(), fmt.Sprint(resp), fmt.Sprintf("%+v", resp)} {
+		if strings.Contains(s, apiKey) || !strings.Contains(s, "apiKey=REDACTED") {
+			t.Errorf("Response.String() = %s, expected redacted API key", s)
+		}
+	}
+
+	server.Close()
+
+	_, _, err = api.Get(ctx, "support@whoisxmlapi.com")
+	if err == nil || strings.Contains(err.Error(), apiKey) || !strings.Contains(err.Error(), "apiKey=REDACTED") {
+		t.Errorf("Evapi.Get() error = %v, expected redacted API key", err)
+	}
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Errorf("Evapi.Get() error = %T, expected to wrap *url.Error", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err = api.GetRaw(cancelled, "support@whoisxmlapi.com")
+	if !errors.Is(err, context.Canceled) || strings.Contains(err.Error(), apiKey) {
+		t.Errorf("Evapi.GetRaw() error = %v, want redacted %v", err, context.Canceled)
+	}
+}
+
+// TestAPIKeyInHeader tests sending of the API key in the header
+func TestAPIKeyInHeader(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Authentication-Token") != apiKey || req.URL.Query().Has("apiKey") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"emailAddress":"support@whoisxmlapi.com"}`))
+	}))
+	defer server.Close()
+
+	api := newAPI(server, "")
+
+	_, _, err := api.Get(context.Background(), "support@whoisxmlapi.com")
+	if !errors.Is(err, ErrAuthentication) {
+		t.Errorf("Evapi.Get() error = %v, want %v", err, ErrAuthentication)
+	}
+
+	api.apiKeyLocation = APIKeyInHeader
+
+	_, resp, err := api.Get(context.Background(), "support@whoisxmlapi.com")
+	if err != nil {
+		t.Errorf("Evapi.Get() error = %v", err)
+	}
+	if strings.Contains(resp.Request.URL.String(), "apiKey") {
+		t.Errorf("Evapi.Get() request URL = %s, expected no API key", resp.Request.URL)
+	}
+}
@@ -0,0 +1,200 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// defaultRetryableStatusCodes are the status codes retried when RetryPolicy.RetryableStatusCodes is empty
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy defines when and how often failed requests are retried
+// The delay between attempts grows exponentially, the Retry-After header and the context deadline are respected
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one. Values less than 2 disable retries
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, it doubles with every next retry. Default: 500ms.
+	BaseDelay time.Duration
+
+	// MaxDelay is the upper limit of the delay between attempts. Default: 30s.
+	// If the server asks to wait longer with the Retry-After header then the request is not retried
+	MaxDelay time.Duration
+
+	// Jitter is the fraction of the delay in range [0, 1] which is randomly subtracted from it. Default: 0.
+	Jitter float64
+
+	// RetryableStatusCodes is the list of response status codes which are retried.
+	// Default: 429, 500, 502, 503, 504.
+	RetryableStatusCodes []int
+
+	// RetryableError reports whether the request failed with the error should be retried.
+	// If it's nil then all errors except the context cancellation are retried
+	RetryableError func(err error) bool
+}
+
+// AttemptsError is returned when the request failed without a response to carry the number of attempts made
+type AttemptsError struct {
+	// Attempts is the number of attempts made
+	Attempts int
+
+	// Err is the error of the last attempt
+	Err error
+}
+
+// Error returns the error message of the last attempt
+func (e *AttemptsError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the last attempt
+func (e *AttemptsError) Unwrap() error {
+	return e.Err
+}
+
+// withAttempts wraps the error with AttemptsError if there is no response to carry the number of attempts
+func withAttempts(resp *Response, err error, attempts int) (*Response, error) {
+	if resp == nil && err != nil {
+		return nil, &AttemptsError{Attempts: attempts, Err: err}
+	}
+	return resp, err
+}
+
+// DefaultRetryPolicy returns the recommended retry policy
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+		Jitter:      0.2,
+	}
+}
+
+// noRetryKey is the context key marking requests which must not be retried
+type noRetryKey struct{}
+
+// withoutRetry returns the context of the request which must not be retried because it's not idempotent
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// backoff returns the delay before the next attempt and false if the request should not be retried
+func (p *RetryPolicy) backoff(ctx context.Context, attempt int, resp *Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || ctx.Value(noRetryKey{}) != nil {
+		return 0, false
+	}
+
+	if !p.retryable(resp, err) {
+		return 0, false
+	}
+
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	delay := p.BaseDelay
+	if delay <= 0 {
+		delay = defaultRetryBaseDelay
+	}
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+
+	if resp != nil && resp.Response != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if retryAfter > maxDelay {
+				return 0, false
+			}
+			if retryAfter > delay {
+				delay = retryAfter
+			}
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+// retryable reports whether the attempt result should be retried
+func (p *RetryPolicy) retryable(resp *Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		if p.RetryableError != nil {
+			return p.RetryableError(err)
+		}
+		return true
+	}
+
+	if resp == nil || resp.Response == nil {
+		return false
+	}
+
+	codes := p.RetryableStatusCodes
+	if len(codes) == 0 {
+		codes = defaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseRetryAfter parses the Retry-After header value given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	if d := date.Sub(now); d > 0 {
+		return d, true
+	}
+
+	return 0, true
+}
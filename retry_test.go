@@ -0,0 +1,276 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer is the sample of the Email Verification API server which fails the first n requests
+func flakyServer(n int32, status int, retryAfter string, resp string) (*httptest.Server, *int32) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= n {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, err := w.Write([]byte(resp))
+		if err != nil {
+			panic(err)
+		}
+	}))
+
+	return server, &calls
+}
+
+// newRetryAPI returns new Email Verification API client with the retry policy for testing
+func newRetryAPI(apiServer *httptest.Server, policy *RetryPolicy) *Client {
+
+	apiURL, err := url.Parse(apiServer.URL)
+	if err != nil {
+		panic(err)
+	}
+
+	return NewClient(apiKey, ClientParams{
+		HTTPClient:   apiServer.Client(),
+		EvapiBaseURL: apiURL,
+		RetryPolicy:  policy,
+	})
+}
+
+// TestRetry tests retrying of failed requests
+func TestRetry(t *testing.T) {
+
+	const resp = `{"emailAddress":"support@whoisxmlapi.com","formatCheck":"true"}`
+
+	fastPolicy := func(maxAttempts int) *RetryPolicy {
+		return &RetryPolicy{
+			MaxAttempts: maxAttempts,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    50 * time.Millisecond,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		failures     int32
+		status       int
+		retryAfter   string
+		policy       *RetryPolicy
+		wantAttempts int
+		wantCalls    int32
+		wantErr      string
+	}{
+		{
+			name:         "no policy",
+			failures:     1,
+			status:       http.StatusServiceUnavailable,
+			policy:       nil,
+			wantAttempts: 1,
+			wantCalls:    1,
+			wantErr:      "API failed with status code: 503",
+		},
+		{
+			name:         "succeeds after failures",
+			failures:     2,
+			status:       http.StatusInternalServerError,
+			policy:       fastPolicy(3),
+			wantAttempts: 3,
+			wantCalls:    3,
+			wantErr:      "",
+		},
+		{
+			name:         "attempts exhausted",
+			failures:     5,
+			status:       http.StatusBadGateway,
+			policy:       fastPolicy(3),
+			wantAttempts: 3,
+			wantCalls:    3,
+			wantErr:      "API failed with status code: 502",
+		},
+		{
+			name:         "non retryable status code",
+			failures:     1,
+			status:       http.StatusBadRequest,
+			policy:       fastPolicy(3),
+			wantAttempts: 1,
+			wantCalls:    1,
+			wantErr:      "API failed with status code: 400",
+		},
+		{
+			name:     "custom status codes",
+			failures: 1,
+			status:   http.StatusConflict,
+			policy: &RetryPolicy{
+				MaxAttempts:          2,
+				BaseDelay:            time.Millisecond,
+				RetryableStatusCodes: []int{http.StatusConflict},
+			},
+			wantAttempts: 2,
+			wantCalls:    2,
+			wantErr:      "",
+		},
+		{
+			name:         "retry after",
+			failures:     1,
+			status:       http.StatusTooManyRequests,
+			retryAfter:   "0",
+			policy:       fastPolicy(2),
+			wantAttempts: 2,
+			wantCalls:    2,
+			wantErr:      "",
+		},
+		{
+			name:         "retry after exceeds max delay",
+			failures:     1,
+			status:       http.StatusTooManyRequests,
+			retryAfter:   "60",
+			policy:       fastPolicy(2),
+			wantAttempts: 1,
+			wantCalls:    1,
+			wantErr:      "API failed with status code: 429",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			server, calls := flakyServer(tt.failures, tt.status, tt.retryAfter, resp)
+			defer server.Close()
+
+			api := newRetryAPI(server, tt.policy)
+
+			got, err := api.GetRaw(context.Background(), "support@whoisxmlapi.com")
+			checkErr(t, err, tt.wantErr)
+
+			if got == nil {
+				t.Fatalf("Evapi.GetRaw() got = nil, expected response")
+			}
+			if got.Attempts != tt.wantAttempts {
+				t.Errorf("Evapi.GetRaw() attempts = %d, want %d", got.Attempts, tt.wantAttempts)
+			}
+			if c := atomic.LoadInt32(calls); c != tt.wantCalls {
+				t.Errorf("server calls = %d, want %d", c, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// TestRetryContext tests that retries respect the context deadline and cancellation
+func TestRetryContext(t *testing.T) {
+
+	server, calls := flakyServer(10, http.StatusServiceUnavailable, "", "")
+	defer server.Close()
+
+	api := newRetryAPI(server, &RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   time.Second,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := api.GetRaw(ctx, "support@whoisxmlapi.com")
+	checkErr(t, err, "API failed with status code: 503")
+
+	if d := time.Since(start); d > 150*time.Millisecond {
+		t.Errorf("Evapi.GetRaw() took %v, expected to give up before the deadline", d)
+	}
+	if resp.Attempts != 1 || atomic.LoadInt32(calls) != 1 {
+		t.Errorf("Evapi.GetRaw() attempts = %d, want 1", resp.Attempts)
+	}
+
+	api = newRetryAPI(server, &RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   50 * time.Millisecond,
+	})
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err = api.GetRaw(ctx, "support@whoisxmlapi.com")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Evapi.GetRaw() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+// TestRetryError tests retrying of transport errors
+func TestRetryError(t *testing.T) {
+
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Content-Length", "100")
+			_, _ = w.Write([]byte(`{"emailAddress"`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"emailAddress":"support@whoisxmlapi.com"}`))
+	}))
+	defer server.Close()
+
+	api := newRetryAPI(server, &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})
+
+	resp, err := api.GetRaw(context.Background(), "support@whoisxmlapi.com")
+	checkErr(t, err, "")
+	if resp.Attempts != 2 {
+		t.Errorf("Evapi.GetRaw() attempts = %d, want 2", resp.Attempts)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	api = newRetryAPI(server, &RetryPolicy{
+		MaxAttempts:    2,
+		BaseDelay:      time.Millisecond,
+		RetryableError: func(err error) bool { return false },
+	})
+
+	_, err = api.GetRaw(context.Background(), "support@whoisxmlapi.com")
+	checkErr(t, err, "cannot read response: unexpected EOF")
+
+	server.Close()
+	api = newRetryAPI(server, &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	resp, err = api.GetRaw(context.Background(), "support@whoisxmlapi.com")
+
+	var errAttempts *AttemptsError
+	if resp != nil || !errors.As(err, &errAttempts) || errAttempts.Attempts != 3 {
+		t.Errorf("Evapi.GetRaw() = %v, %v, expected AttemptsError with 3 attempts", resp, err)
+	}
+}
+
+// TestParseRetryAfter tests parsing of the Retry-After header
+func TestParseRetryAfter(t *testing.T) {
+
+	now := time.Date(2022, 4, 3, 5, 2, 37, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{value: "", want: 0, wantOk: false},
+		{value: "120", want: 2 * time.Minute, wantOk: true},
+		{value: "-1", want: 0, wantOk: false},
+		{value: now.Add(time.Minute).Format(http.TimeFormat), want: time.Minute, wantOk: true},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, wantOk: true},
+		{value: "soon", want: 0, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Quote(tt.value), func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("parseRetryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package emailverifier
+
+import "strings"
+
+const defaultSuggestMaxDistance = 2
+
+// shortSuggestName is the maximum length of the provider names which are corrected only at the edit distance 1
+const shortSuggestName = 5
+
+// defaultSuggestDomains are the popular email provider domains used by DefaultSuggester
+var defaultSuggestDomains = []string{
+	"gmail.com", "yahoo.com", "hotmail.com", "outlook.com", "aol.com", "icloud.com", "live.com", "msn.com",
+	"googlemail.com", "ymail.com", "me.com", "mac.com", "mail.com", "gmx.com", "gmx.de", "gmx.net", "web.de",
+	"protonmail.com", "proton.me", "zoho.com", "yandex.com", "yandex.ru", "mail.ru", "qq.com", "163.com",
+	"comcast.net", "verizon.net", "att.net", "sbcglobal.net", "bellsouth.net", "cox.net", "charter.net",
+	"hotmail.co.uk", "hotmail.fr", "hotmail.de", "hotmail.it", "yahoo.co.uk", "yahoo.fr", "yahoo.de",
+	"yahoo.ca", "yahoo.co.in", "yahoo.co.jp", "yahoo.com.br", "live.co.uk", "live.fr", "outlook.de",
+	"orange.fr", "laposte.net", "libero.it", "t-online.de", "btinternet.com", "rediffmail.com",
+}
+
+// defaultSuggestTLDs are the top-level domains used by DefaultSuggester in the order of preference
+var defaultSuggestTLDs = []string{
+	"com", "net", "org", "edu", "gov", "io", "co", "us", "uk", "co.uk", "org.uk", "ac.uk", "de", "fr", "it",
+	"es", "nl", "be", "ch", "at", "se", "no", "dk", "fi", "pl", "cz", "ru", "ua", "jp", "co.jp", "cn", "in",
+	"co.in", "br", "com.br", "ca", "au", "com.au", "nz", "co.nz", "mx", "com.mx", "ar", "com.ar", "za",
+	"co.za", "ie", "pt", "gr", "tr", "il", "kr", "sg", "hk", "tw", "info", "biz", "me", "app", "dev", "ai",
+}
+
+// Suggester proposes corrections of mistyped email address domains, e.g. gmial.com or hotmail.con
+// It works offline comparing the domain with the popular provider domains and the top-level domains
+type Suggester struct {
+	// Domains are the popular provider domains. Earlier domains win ties
+	// A domain differing from them only in a known top-level domain, e.g. hotmail.es, is not corrected
+	Domains []string
+
+	// TLDs are the known top-level domains, including multi-label ones like co.uk. Earlier TLDs win ties
+	// The top-level domain is corrected if the whole domain doesn't match any of Domains
+	TLDs []string
+
+	// MaxDistance is the maximum edit distance between the domain and the suggested domain. Default: 2.
+	// Short provider names like gmx and top-level domains are corrected only at the edit distance 1
+	MaxDistance int
+}
+
+// DefaultSuggester returns the suggester with the popular provider domains and top-level domains
+// The lists are copied, so they can be changed without affecting the others
+func DefaultSuggester() *Suggester {
+	return &Suggester{
+		Domains:     append([]string(nil), defaultSuggestDomains...),
+		TLDs:        append([]string(nil), defaultSuggestTLDs...),
+		MaxDistance: defaultSuggestMaxDistance,
+	}
+}
+
+// Suggest returns the corrected email address using DefaultSuggester or an empty string if there is no suggestion
+func Suggest(emailAddress string) string {
+	return DefaultSuggester().Suggest(emailAddress)
+}
+
+// Suggest returns the corrected email address or an empty string if there is no suggestion
+func (s *Suggester) Suggest(emailAddress string) string {
+	emailAddress = strings.TrimSpace(emailAddress)
+
+	at := strings.LastIndexByte(emailAddress, '@')
+	if at <= 0 || at == len(emailAddress)-1 {
+		return ""
+	}
+
+	localPart := emailAddress[:at]
+	domain := strings.TrimSuffix(strings.ToLower(emailAddress[at+1:]), ".")
+
+	if corrected := s.suggestDomain(domain); corrected != "" && corrected != domain {
+		return localPart + "@" + corrected
+	}
+
+	return ""
+}
+
+// suggestDomain returns the corrected domain
+func (s *Suggester) suggestDomain(domain string) string {
+	maxDistance := s.MaxDistance
+	if maxDistance <= 0 {
+		maxDistance = defaultSuggestMaxDistance
+	}
+
+	name, known := s.splitTLD(domain)
+
+	best, bestDistance := "", maxDistance+1
+	for _, d := range s.Domains {
+		d = strings.ToLower(d)
+		if d == domain {
+			return ""
+		}
+
+		// The same provider in another valid country domain, e.g. hotmail.es for hotmail.fr, is not a typo
+		dName, _ := s.splitTLD(d)
+		if known && dName == name {
+			continue
+		}
+
+		// Short names are too close to each other to be corrected reliably, e.g. web.com and me.com
+		limit := maxDistance
+		if len([]rune(dName)) <= shortSuggestName {
+			limit = 1
+		}
+
+		if dist := editDistance(domain, d); dist < bestDistance && dist <= limit && dist < len(d)/2 {
+			best, bestDistance = d, dist
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	return s.suggestTLD(domain)
+}
+
+// splitTLD returns the domain without the longest known top-level domain, e.g. yahoo for yahoo.com.au
+// If the top-level domain is not in TLDs then the last label is removed and known is false
+func (s *Suggester) splitTLD(domain string) (name string, known bool) {
+	tld := ""
+	for _, t := range s.TLDs {
+		t = strings.ToLower(t)
+		if strings.HasSuffix(domain, "."+t) && len(t) > len(tld) {
+			tld = t
+		}
+	}
+	if tld != "" {
+		return domain[:len(domain)-len(tld)-1], true
+	}
+
+	if dot := strings.LastIndexByte(domain, '.'); dot >= 0 {
+		return domain[:dot], false
+	}
+	return domain, false
+}
+
+// suggestTLD returns the domain with the corrected top-level domain
+func (s *Suggester) suggestTLD(domain string) string {
+	dot := strings.LastIndexByte(domain, '.')
+	if dot <= 0 {
+		return ""
+	}
+
+	for _, tld := range s.TLDs {
+		if strings.HasSuffix(domain, "."+strings.ToLower(tld)) {
+			return ""
+		}
+	}
+
+	name, tld := domain[:dot], domain[dot+1:]
+	for _, known := range s.TLDs {
+		known = strings.ToLower(known)
+		if !strings.Contains(known, ".") && editDistance(tld, known) == 1 {
+			return name + "." + known
+		}
+	}
+
+	return ""
+}
+
+// attach sets EvapiResponse.Suggestion if the format or DNS check failed
+func (s *Suggester) attach(r *EvapiResponse) {
+	if (r.FormatCheck != nil && !bool(*r.FormatCheck)) || (r.DnsCheck != nil && !bool(*r.DnsCheck)) {
+		r.Suggestion = s.Suggest(r.EmailAddress)
+	}
+}
+
+// editDistance returns the optimal string alignment distance between the strings: the number of insertions,
+// deletions, substitutions and transpositions of adjacent characters
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// prev2, prev and cur are the rows of the distance matrix
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = minInt(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	return prev[len(rb)]
+}
+
+// minInt returns the smaller of the integers
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
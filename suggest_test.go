@@ -0,0 +1,121 @@
+package emailverifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestSuggest tests the suggestions with the default suggester
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		emailAddress string
+		want         string
+	}{
+		{"john@gmial.com", "john@gmail.com"},
+		{"john@hotmial.con", "john@hotmail.com"},
+		{"john@yaho.com", "john@yahoo.com"},
+		{"John.Doe@GMAIL.CMO", "John.Doe@gmail.com"},
+		{"john@gmail,com", "john@gmail.com"},
+		{"john@outlok.com.", "john@outlook.com"},
+		{"john@whoisxmlapi.cmo", "john@whoisxmlapi.com"},
+		{"john@whoisxmlapi.con", "john@whoisxmlapi.com"},
+		{"john@example.co.uk", ""},
+		{"john@gmail.com", ""},
+		{"john@gmx.com", ""},
+		{"john@whoisxmlapi.com", ""},
+		{"john@aol.co", ""},
+		{"john@aol.cmo", "john@aol.com"},
+		{"a@hotmail.es", ""},
+		{"a@yahoo.it", ""},
+		{"a@yahoo.es", ""},
+		{"a@outlook.fr", ""},
+		{"a@gmx.at", ""},
+		{"a@live.nl", ""},
+		{"a@gmx.ch", ""},
+		{"a@yahoo.com.au", ""},
+		{"a@mail.de", ""},
+		{"a@web.com", ""},
+		{"a@hotmail.co.uk", ""},
+		{"a@wbe.de", "a@web.de"},
+		{"john@localhost", ""},
+		{"john", ""},
+		{"@gmial.com", ""},
+		{"john@", ""},
+	}
+	for _, tt := range tests {
+		if got := Suggest(tt.emailAddress); got != tt.want {
+			t.Errorf("Suggest(%q) = %q, want %q", tt.emailAddress, got, tt.want)
+		}
+	}
+
+	s := DefaultSuggester()
+	s.Domains = append(s.Domains, "whoisxmlapi.com")
+	if got := s.Suggest("john@whoisxmlapl.com"); got != "john@whoisxmlapi.com" {
+		t.Errorf("Suggest() with custom domains = %q", got)
+	}
+}
+
+// TestEditDistance tests the edit distance
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"gmail", "gmial", 1},
+		{"hotmail.com", "hotmial.con", 2},
+		{"kitten", "sitting", 3},
+		{"bücher", "bucher", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := editDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+// TestSuggesterGet tests the suggestions attached to the responses
+func TestSuggesterGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		emailAddress := req.URL.Query().Get("emailAddress")
+		_, _ = w.Write([]byte(`{"emailAddress":"` + emailAddress + `","formatCheck":"true","dnsCheck":"false"}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := NewClient(apiKey, ClientParams{
+		HTTPClient:     server.Client(),
+		EvapiBaseURL:   apiURL,
+		ValidateSyntax: true,
+		Suggester:      DefaultSuggester(),
+	})
+
+	tests := []struct {
+		emailAddress string
+		want         string
+	}{
+		{"john@gmial.com", "john@gmail.com"},
+		{"john@gmail,com", "john@gmail.com"},
+		{"john@whoisxmlapi.com", ""},
+	}
+	for _, tt := range tests {
+		evapiResp, _, err := api.Get(context.Background(), tt.emailAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if evapiResp.Suggestion != tt.want {
+			t.Errorf("Get(%q) suggestion = %q, want %q", tt.emailAddress, evapiResp.Suggestion, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package emailverifier
+
+import (
+	"net"
+	"strings"
+	"unicode/utf8"
+)
+
+// Email address length limits defined in RFC 5321
+const (
+	maxAddressLength   = 254
+	maxLocalPartLength = 64
+	maxDomainLength    = 253
+	maxLabelLength     = 63
+)
+
+// SyntaxError is returned when the email address syntax is invalid
+type SyntaxError struct {
+	// EmailAddress is the validated email address
+	EmailAddress string
+
+	// Reason describes the syntax violation
+	Reason string
+}
+
+// Error returns error message as a string
+func (e *SyntaxError) Error() string {
+	return `invalid email address "` + e.EmailAddress + `": ` + e.Reason
+}
+
+// ValidateAddress checks the email address syntax according to RFC 5321 and RFC 5322 without network access.
+// Quoted local parts, address literals and UTF-8 characters allowed by RFC 6531 are accepted,
+// comments and folding white space are not
+func ValidateAddress(emailAddress string) error {
+	fail := func(reason string) error {
+		return &SyntaxError{EmailAddress: emailAddress, Reason: reason}
+	}
+
+	if emailAddress == "" {
+		return fail("empty address")
+	}
+	if !utf8.ValidString(emailAddress) {
+		return fail("invalid UTF-8")
+	}
+	if len(emailAddress) > maxAddressLength {
+		return fail("address is longer than 254 octets")
+	}
+
+	localPart, domain, reason := splitLocalPart(emailAddress)
+	if reason != "" {
+		return fail(reason)
+	}
+
+	if len(localPart) > maxLocalPartLength {
+		return fail("local part is longer than 64 octets")
+	}
+
+	if reason = validateDomain(domain); reason != "" {
+		return fail(reason)
+	}
+
+	return nil
+}
+
+// splitLocalPart parses the local part of the address and returns it with the domain
+// The non-empty reason describes the syntax violation
+func splitLocalPart(emailAddress string) (localPart, domain, reason string) {
+	if strings.HasPrefix(emailAddress, `"`) {
+		i := 1
+		for ; i < len(emailAddress) && emailAddress[i] != '"'; i++ {
+			c := emailAddress[i]
+			switch {
+			case c == '\\':
+				i++
+				if i == len(emailAddress) || !isQuotedPair(emailAddress[i]) {
+					return "", "", "invalid quoted pair in local part"
+				}
+			case !isQText(c):
+				return "", "", "invalid character in quoted local part"
+			}
+		}
+		if i == len(emailAddress) {
+			return "", "", "unterminated quoted local part"
+		}
+		if i == 1 {
+			return "", "", "empty quoted local part"
+		}
+		if i+1 == len(emailAddress) || emailAddress[i+1] != '@' {
+			return "", "", "quoted local part must be followed by @"
+		}
+		return emailAddress[:i+1], emailAddress[i+2:], ""
+	}
+
+	at := strings.IndexByte(emailAddress, '@')
+	if at < 0 {
+		return "", "", "missing @"
+	}
+
+	localPart, domain = emailAddress[:at], emailAddress[at+1:]
+	if localPart == "" {
+		return "", "", "empty local part"
+	}
+
+	for _, atom := range strings.Split(localPart, ".") {
+		if atom == "" {
+			return "", "", "misplaced dot in local part"
+		}
+		for i := 0; i < len(atom); i++ {
+			if !isAText(atom[i]) {
+				return "", "", "invalid character in local part"
+			}
+		}
+	}
+
+	return localPart, domain, ""
+}
+
+// validateDomain checks the domain name or address literal syntax
+// and returns the non-empty reason of the syntax violation
+func validateDomain(domain string) string {
+	if domain == "" {
+		return "empty domain"
+	}
+
+	if strings.HasPrefix(domain, "[") {
+		if !strings.HasSuffix(domain, "]") {
+			return "unterminated address literal"
+		}
+		literal := domain[1 : len(domain)-1]
+		if strings.HasPrefix(literal, "IPv6:") {
+			ip := net.ParseIP(literal[len("IPv6:"):])
+			if ip == nil || ip.To4() != nil && !strings.Contains(literal[len("IPv6:"):], ":") {
+				return "invalid IPv6 address literal"
+			}
+			return ""
+		}
+		if ip := net.ParseIP(literal); ip == nil || ip.To4() == nil || strings.Contains(literal, ":") {
+			return "invalid IPv4 address literal"
+		}
+		return ""
+	}
+
+	if len(domain) > maxDomainLength {
+		return "domain is longer than 253 octets"
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "domain must have at least two labels"
+	}
+
+	for _, label := range labels {
+		if label == "" {
+			return "empty domain label"
+		}
+		if len(label) > maxLabelLength {
+			return "domain label is longer than 63 octets"
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return "domain label must not start or end with hyphen"
+		}
+		for i := 0; i < len(label); i++ {
+			if c := label[i]; !isLetterDigit(c) && c != '-' && c < utf8.RuneSelf {
+				return "invalid character in domain"
+			}
+		}
+	}
+
+	tld := labels[len(labels)-1]
+	if strings.Trim(tld, "0123456789") == "" {
+		return "top-level domain must not be numeric"
+	}
+
+	return ""
+}
+
+// isLetterDigit reports whether c is an ASCII letter or digit
+func isLetterDigit(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// isAText reports whether c is allowed in an unquoted local part. UTF-8 octets are allowed by RFC 6531
+func isAText(c byte) bool {
+	return isLetterDigit(c) || strings.IndexByte("!#$%&'*+-/=?^_`{|}~", c) >= 0 || c >= utf8.RuneSelf
+}
+
+// isQText reports whether c is allowed in a quoted local part without escaping
+func isQText(c byte) bool {
+	return c == ' ' || c == '\t' || c > ' ' && c != '"' && c != '\\' && c != 0x7f
+}
+
+// isQuotedPair reports whether c can be escaped with a backslash in a quoted local part
+func isQuotedPair(c byte) bool {
+	return c == ' ' || c == '\t' || c > ' ' && c != 0x7f
+}
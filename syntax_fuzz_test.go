@@ -0,0 +1,44 @@
+//go:build go1.18
+
+package emailverifier
+
+import (
+	"strings"
+	"testing"
+)
+
+// FuzzValidateAddress tests that ValidateAddress never panics and accepts only well-formed addresses
+func FuzzValidateAddress(f *testing.F) {
+	for _, seed := range []string{
+		"support@whoisxmlapi.com",
+		`"john\"doe"@example.com`,
+		"user@[IPv6:2001:db8::1]",
+		"user@[192.168.0.1]",
+		"josé@пример.рф",
+		"foo@@bar",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, address string) {
+		if ValidateAddress(address) != nil {
+			return
+		}
+
+		if len(address) > maxAddressLength {
+			t.Errorf("ValidateAddress(%q) accepted address longer than %d octets", address, maxAddressLength)
+		}
+
+		i := strings.LastIndexByte(address, '@')
+		if i < 1 || i == len(address)-1 {
+			t.Errorf("ValidateAddress(%q) accepted address without local part or domain", address)
+			return
+		}
+
+		if domain := address[i+1:]; !strings.HasPrefix(domain, "[") &&
+			(strings.Contains(domain, "..") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".")) {
+			t.Errorf("ValidateAddress(%q) accepted domain with empty label", address)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestValidateAddress tests the local email address syntax validation
+func TestValidateAddress(t *testing.T) {
+
+	tests := []struct {
+		address string
+		wantErr string
+	}{
+		{address: "support@whoisxmlapi.com", wantErr: ""},
+		{address: "first.last+tag@sub.example.co.uk", wantErr: ""},
+		{address: "!#$%&'*+-/=?^_`{|}~@example.com", wantErr: ""},
+		{address: `"john doe"@example.com`, wantErr: ""},
+		{address: `"john@doe"@example.com`, wantErr: ""},
+		{address: `"john\"doe"@example.com`, wantErr: ""},
+		{address: "user@[192.168.0.1]", wantErr: ""},
+		{address: "user@[IPv6:2001:db8::1]", wantErr: ""},
+		{address: "josé@example.com", wantErr: ""},
+		{address: "user@пример.рф", wantErr: ""},
+		{address: "user@xn--e1afmkfd.xn--p1ai", wantErr: ""},
+		{address: strings.Repeat("a", 64) + "@example.com", wantErr: ""},
+		{address: "", wantErr: "empty address"},
+		{address: "support", wantErr: "missing @"},
+		{address: "@example.com", wantErr: "empty local part"},
+		{address: "foo@@bar.com", wantErr: "invalid character in domain"},
+		{address: "foo@", wantErr: "empty domain"},
+		{address: ".foo@example.com", wantErr: "misplaced dot in local part"},
+		{address: "foo.@example.com", wantErr: "misplaced dot in local part"},
+		{address: "foo..bar@example.com", wantErr: "misplaced dot in local part"},
+		{address: "foo bar@example.com", wantErr: "invalid character in local part"},
+		{address: "foo(comment)@example.com", wantErr: "invalid character in local part"},
+		{address: `"foo@example.com`, wantErr: "unterminated quoted local part"},
+		{address: `""@example.com`, wantErr: "empty quoted local part"},
+		{address: `"foo"bar@example.com`, wantErr: "quoted local part must be followed by @"},
+		{address: "\"foo\x01\"@example.com", wantErr: "invalid character in quoted local part"},
+		{address: strings.Repeat("a", 65) + "@example.com", wantErr: "local part is longer than 64 octets"},
+		{address: "a@" + strings.Repeat("b", 250) + ".com", wantErr: "address is longer than 254 octets"},
+		{address: "user@localhost", wantErr: "domain must have at least two labels"},
+		{address: "user@example..com", wantErr: "empty domain label"},
+		{address: "user@example.com.", wantErr: "empty domain label"},
+		{address: "user@-example.com", wantErr: "domain label must not start or end with hyphen"},
+		{address: "user@example-.com", wantErr: "domain label must not start or end with hyphen"},
+		{address: "user@exa_mple.com", wantErr: "invalid character in domain"},
+		{address: "user@" + strings.Repeat("a", 64) + ".com", wantErr: "domain label is longer than 63 octets"},
+		{address: "user@1.2.3.4", wantErr: "top-level domain must not be numeric"},
+		{address: "user@[1.2.3.4", wantErr: "unterminated address literal"},
+		{address: "user@[1.2.3]", wantErr: "invalid IPv4 address literal"},
+		{address: "user@[IPv6:1.2.3.4]", wantErr: "invalid IPv6 address literal"},
+		{address: "user\xff@example.com", wantErr: "invalid UTF-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := ValidateAddress(tt.address)
+			if tt.wantErr == "" {
+				checkErr(t, err, "")
+				return
+			}
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) || syntaxErr.Reason != tt.wantErr {
+				t.Errorf("ValidateAddress() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestEvapiGetValidateSyntax tests skipping of the API request for addresses with invalid syntax
+func TestEvapiGetValidateSyntax(t *testing.T) {
+
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"emailAddress":"support@whoisxmlapi.com","formatCheck":"true"}`))
+	}))
+	defer server.Close()
+
+	api := newAPI(server, "")
+	api.validateSyntax = true
+
+	ctx := context.Background()
+
+	evapiResp, resp, err := api.Get(ctx, "foo@@bar.com")
+	if err != nil {
+		t.Fatalf("Evapi.Get() error = %v", err)
+	}
+	if evapiResp.FormatCheck == nil || bool(*evapiResp.FormatCheck) || evapiResp.Username != "foo@" ||
+		evapiResp.Domain != "bar.com" || evapiResp.EmailAddress != "foo@@bar.com" {
+		t.Errorf("Evapi.Get() got = %+v, expected failed format check", evapiResp)
+	}
+	if !resp.Synthesized || resp.StatusCode != http.StatusOK ||
+		!strings.Contains(string(resp.Body), `"formatCheck":"false"`) {
+		t.Errorf("Evapi.Get() response = %+v, expected synthesized response", resp)
+	}
+	if c := atomic.LoadInt32(&calls); c != 0 {
+		t.Errorf("server calls = %d, want 0", c)
+	}
+
+	evapiResp, resp, err = api.Get(ctx, "support@whoisxmlapi.com")
+	if err != nil || !bool(*evapiResp.FormatCheck) || resp.Synthesized {
+		t.Errorf("Evapi.Get() = %+v, %v, expected API response", evapiResp, err)
+	}
+	if c := atomic.LoadInt32(&calls); c != 1 {
+		t.Errorf("server calls = %d, want 1", c)
+	}
+
+	_, _, err = api.Get(ctx, "")
+	checkErr(t, err, `invalid argument: "emailAddress" cannot be empty`)
+}
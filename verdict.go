@@ -0,0 +1,223 @@
+package emailverifier
+
+import "fmt"
+
+// Verdict is the interpretation of the Email Verification API checks
+type Verdict string
+
+// Verdicts returned by ScoringPolicy.Verdict
+const (
+	// VerdictDeliverable means that the address passed the checks
+	VerdictDeliverable Verdict = "deliverable"
+
+	// VerdictRisky means that the address is likely to receive emails but it's catch-all, disposable, etc.
+	VerdictRisky Verdict = "risky"
+
+	// VerdictUndeliverable means that the address can't receive emails
+	VerdictUndeliverable Verdict = "undeliverable"
+
+	// VerdictUnknown means that the checks required for the verdict were not performed
+	VerdictUnknown Verdict = "unknown"
+)
+
+// Check is the Email Verification API check named as the response field
+type Check string
+
+// Email Verification API checks
+const (
+	CheckFormat     Check = "formatCheck"
+	CheckDNS        Check = "dnsCheck"
+	CheckSMTP       Check = "smtpCheck"
+	CheckCatchAll   Check = "catchAllCheck"
+	CheckDisposable Check = "disposableCheck"
+	CheckFree       Check = "freeCheck"
+)
+
+// CheckStatus is the result of the check
+type CheckStatus string
+
+const (
+	// CheckPassed means that the check found nothing bad about the address
+	CheckPassed CheckStatus = "passed"
+
+	// CheckFlagged means that the check failed for formatCheck, dnsCheck and smtpCheck,
+	// or the address is catch-all, disposable or free for the other checks
+	CheckFlagged CheckStatus = "flagged"
+
+	// CheckUnchecked means that the check was not performed, i.e. the field is nil
+	CheckUnchecked CheckStatus = "unchecked"
+)
+
+// Status returns the check status in the response
+func (c Check) Status(r *EvapiResponse) CheckStatus {
+	var value *StringBool
+	flaggedIf := false
+
+	switch c {
+	case CheckFormat:
+		value = r.FormatCheck
+	case CheckDNS:
+		value = r.DnsCheck
+	case CheckSMTP:
+		value = r.SmtpCheck
+	case CheckCatchAll:
+		value, flaggedIf = r.CatchAllCheck, true
+	case CheckDisposable:
+		value, flaggedIf = r.DisposableCheck, true
+	case CheckFree:
+		value, flaggedIf = r.FreeCheck, true
+	}
+
+	switch {
+	case value == nil:
+		return CheckUnchecked
+	case bool(*value) == flaggedIf:
+		return CheckFlagged
+	}
+	return CheckPassed
+}
+
+// CheckWeight is the risk score added by the check
+type CheckWeight struct {
+	// Flagged is added when the check flagged the address
+	Flagged int `json:"flagged" yaml:"flagged"`
+
+	// Unchecked is added when the check was not performed
+	Unchecked int `json:"unchecked" yaml:"unchecked"`
+}
+
+// RiskReason is the check which added to the risk score
+type RiskReason struct {
+	// Check is the check name
+	Check Check `json:"check"`
+
+	// Status is CheckFlagged or CheckUnchecked
+	Status CheckStatus `json:"status"`
+
+	// Score is the added score
+	Score int `json:"score"`
+}
+
+// String returns the reason as "check status (+score)"
+func (r RiskReason) String() string {
+	return fmt.Sprintf("%s %s (+%d)", r.Check, r.Status, r.Score)
+}
+
+// maxRiskScore is the upper limit of the risk score
+const maxRiskScore = 100
+
+// ScoringPolicy defines how the checks are combined into the risk score and the verdict
+// The risk score is the sum of the check weights limited to 100
+type ScoringPolicy struct {
+	// Weights are the check weights. Checks missing in the map don't affect the score
+	Weights map[Check]CheckWeight `json:"weights" yaml:"weights"`
+
+	// RiskyScore is the minimum score of risky addresses
+	RiskyScore int `json:"riskyScore" yaml:"riskyScore"`
+
+	// UndeliverableScore is the minimum flagged weight of the checks which make the address undeliverable
+	// on their own, e.g. failed format, DNS and SMTP checks. Default: 100.
+	// Lighter checks don't make the address undeliverable however high their total score is
+	UndeliverableScore int `json:"undeliverableScore" yaml:"undeliverableScore"`
+
+	// Required are the checks which must be performed for a verdict other than unknown or undeliverable
+	Required []Check `json:"required" yaml:"required"`
+}
+
+// DefaultScoringPolicy returns the scoring policy used by EvapiResponse.Verdict and EvapiResponse.RiskScore
+// Failed format, DNS and SMTP checks make the address undeliverable,
+// catch-all and disposable addresses are risky, unknown SMTP check result makes the verdict unknown
+func DefaultScoringPolicy() *ScoringPolicy {
+	return &ScoringPolicy{
+		Weights: map[Check]CheckWeight{
+			CheckFormat:     {Flagged: 100},
+			CheckDNS:        {Flagged: 100, Unchecked: 10},
+			CheckSMTP:       {Flagged: 100, Unchecked: 30},
+			CheckCatchAll:   {Flagged: 40, Unchecked: 10},
+			CheckDisposable: {Flagged: 70, Unchecked: 10},
+			CheckFree:       {Flagged: 10},
+		},
+		RiskyScore:         30,
+		UndeliverableScore: 100,
+		Required:           []Check{CheckFormat, CheckSMTP},
+	}
+}
+
+// allChecks is the order of the checks in the risk reasons
+var allChecks = []Check{CheckFormat, CheckDNS, CheckSMTP, CheckCatchAll, CheckDisposable, CheckFree}
+
+// RiskScore returns the risk score in range [0, 100] and the checks which added to it
+func (p *ScoringPolicy) RiskScore(r *EvapiResponse) (int, []RiskReason) {
+	if p == nil {
+		p = DefaultScoringPolicy()
+	}
+
+	score := 0
+	var reasons []RiskReason
+
+	for _, check := range allChecks {
+		weight := p.Weights[check]
+
+		var reason RiskReason
+		switch status := check.Status(r); status {
+		case CheckFlagged:
+			reason = RiskReason{Check: check, Status: status, Score: weight.Flagged}
+		case CheckUnchecked:
+			reason = RiskReason{Check: check, Status: status, Score: weight.Unchecked}
+		}
+
+		if reason.Score > 0 {
+			score += reason.Score
+			reasons = append(reasons, reason)
+		}
+	}
+
+	if score > maxRiskScore {
+		score = maxRiskScore
+	}
+
+	return score, reasons
+}
+
+// Verdict returns the verdict for the response
+// The address is undeliverable if any check with the flagged weight of at least UndeliverableScore is flagged,
+// otherwise the verdict is unknown if any required check was not performed,
+// risky if the score reaches RiskyScore and deliverable if not
+func (p *ScoringPolicy) Verdict(r *EvapiResponse) Verdict {
+	if p == nil {
+		p = DefaultScoringPolicy()
+	}
+
+	undeliverableScore := p.UndeliverableScore
+	if undeliverableScore <= 0 {
+		undeliverableScore = maxRiskScore
+	}
+
+	for _, check := range allChecks {
+		if p.Weights[check].Flagged >= undeliverableScore && check.Status(r) == CheckFlagged {
+			return VerdictUndeliverable
+		}
+	}
+
+	for _, check := range p.Required {
+		if check.Status(r) == CheckUnchecked {
+			return VerdictUnknown
+		}
+	}
+
+	if score, _ := p.RiskScore(r); score > 0 && score >= p.RiskyScore {
+		return VerdictRisky
+	}
+
+	return VerdictDeliverable
+}
+
+// Verdict returns the verdict according to DefaultScoringPolicy
+func (r *EvapiResponse) Verdict() Verdict {
+	return DefaultScoringPolicy().Verdict(r)
+}
+
+// RiskScore returns the risk score according to DefaultScoringPolicy and the checks which added to it
+func (r *EvapiResponse) RiskScore() (int, []RiskReason) {
+	return DefaultScoringPolicy().RiskScore(r)
+}
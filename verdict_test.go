@@ -0,0 +1,165 @@
+package emailverifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+// checks creates the response with the check values, nil means not checked
+func checks(format, dns, smtp, catchAll, disposable, free *StringBool) *EvapiResponse {
+	return &EvapiResponse{
+		FormatCheck:     format,
+		DnsCheck:        dns,
+		SmtpCheck:       smtp,
+		CatchAllCheck:   catchAll,
+		DisposableCheck: disposable,
+		FreeCheck:       free,
+	}
+}
+
+// TestVerdict tests the verdict and the risk score with the default scoring policy
+func TestVerdict(t *testing.T) {
+	yes, no := boolPtr(true), boolPtr(false)
+
+	tests := []struct {
+		name        string
+		response    *EvapiResponse
+		wantVerdict Verdict
+		wantScore   int
+		wantReasons []RiskReason
+	}{
+		{
+			name:        "deliverable",
+			response:    checks(yes, yes, yes, no, no, no),
+			wantVerdict: VerdictDeliverable,
+			wantScore:   0,
+		},
+		{
+			name:        "free",
+			response:    checks(yes, yes, yes, no, no, yes),
+			wantVerdict: VerdictDeliverable,
+			wantScore:   10,
+			wantReasons: []RiskReason{{CheckFree, CheckFlagged, 10}},
+		},
+		{
+			name:        "catch-all",
+			response:    checks(yes, yes, yes, yes, no, no),
+			wantVerdict: VerdictRisky,
+			wantScore:   40,
+			wantReasons: []RiskReason{{CheckCatchAll, CheckFlagged, 40}},
+		},
+		{
+			name:        "disposable not checked",
+			response:    checks(yes, yes, yes, no, nil, no),
+			wantVerdict: VerdictDeliverable,
+			wantScore:   10,
+			wantReasons: []RiskReason{{CheckDisposable, CheckUnchecked, 10}},
+		},
+		{
+			name:        "SMTP failed",
+			response:    checks(yes, yes, no, nil, nil, nil),
+			wantVerdict: VerdictUndeliverable,
+			wantScore:   100,
+			wantReasons: []RiskReason{
+				{CheckSMTP, CheckFlagged, 100},
+				{CheckCatchAll, CheckUnchecked, 10},
+				{CheckDisposable, CheckUnchecked, 10},
+			},
+		},
+		{
+			name:        "invalid format",
+			response:    checks(no, nil, nil, nil, nil, nil),
+			wantVerdict: VerdictUndeliverable,
+			wantScore:   100,
+		},
+		{
+			name:        "SMTP not checked",
+			response:    checks(yes, yes, nil, no, no, no),
+			wantVerdict: VerdictUnknown,
+			wantScore:   30,
+			wantReasons: []RiskReason{{CheckSMTP, CheckUnchecked, 30}},
+		},
+		{
+			name:        "catch-all and disposable",
+			response:    checks(yes, yes, yes, yes, yes, no),
+			wantVerdict: VerdictRisky,
+			wantScore:   100,
+			wantReasons: []RiskReason{
+				{CheckCatchAll, CheckFlagged, 40},
+				{CheckDisposable, CheckFlagged, 70},
+			},
+		},
+		{
+			name:        "SMTP not checked and disposable",
+			response:    checks(yes, yes, nil, no, yes, no),
+			wantVerdict: VerdictUnknown,
+			wantScore:   100,
+			wantReasons: []RiskReason{
+				{CheckSMTP, CheckUnchecked, 30},
+				{CheckDisposable, CheckFlagged, 70},
+			},
+		},
+		{
+			name:        "nothing checked",
+			response:    &EvapiResponse{},
+			wantVerdict: VerdictUnknown,
+			wantScore:   60,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.response.Verdict(); got != tt.wantVerdict {
+				t.Errorf("Verdict() = %v, want %v", got, tt.wantVerdict)
+			}
+
+			score, reasons := tt.response.RiskScore()
+			if score != tt.wantScore {
+				t.Errorf("RiskScore() = %v, want %v, reasons: %v", score, tt.wantScore, reasons)
+			}
+			if tt.wantReasons != nil && !reflect.DeepEqual(reasons, tt.wantReasons) {
+				t.Errorf("RiskScore() reasons = %v, want %v", reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+// TestScoringPolicy tests the custom scoring policy
+func TestScoringPolicy(t *testing.T) {
+	yes, no := boolPtr(true), boolPtr(false)
+
+	policy := &ScoringPolicy{
+		Weights: map[Check]CheckWeight{
+			CheckSMTP: {Flagged: 100},
+			CheckFree: {Flagged: 50},
+		},
+		RiskyScore: 50,
+	}
+
+	tests := []struct {
+		response *EvapiResponse
+		want     Verdict
+	}{
+		{checks(yes, yes, nil, yes, yes, no), VerdictDeliverable},
+		{checks(yes, yes, yes, no, no, yes), VerdictRisky},
+		{checks(yes, yes, no, no, no, no), VerdictUndeliverable},
+	}
+	for _, tt := range tests {
+		if got := policy.Verdict(tt.response); got != tt.want {
+			t.Errorf("Verdict(%+v) = %v, want %v", tt.response, got, tt.want)
+		}
+	}
+
+	if got := (*ScoringPolicy)(nil).Verdict(checks(yes, yes, yes, no, no, no)); got != VerdictDeliverable {
+		t.Errorf("nil policy Verdict() = %v, want %v", got, VerdictDeliverable)
+	}
+
+	if got := (RiskReason{CheckSMTP, CheckUnchecked, 30}).String(); got != "smtpCheck unchecked (+30)" {
+		t.Errorf("RiskReason.String() = %v", got)
+	}
+}
+
+// boolPtr returns a pointer to the StringBool value
+func boolPtr(v bool) *StringBool {
+	b := StringBool(v)
+	return &b
+}